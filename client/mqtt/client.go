@@ -4,23 +4,34 @@ import (
 	"sync"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
-	klog "github.com/jiushengTech/common/log/klog/logger"
+	klog "github.com/go-kratos/kratos/v2/log"
+	"github.com/jiushengTech/common/log"
 )
 
 type Client struct {
 	mqtt.Client
 	subscriptions *sync.Map // 使用sync.Map来替代map
+	log           *klog.Helper
 }
 
-func NewClient(opts *mqtt.ClientOptions) *Client {
-	klog.Init()
+func NewClient(opts *mqtt.ClientOptions, options ...ClientOption) *Client {
 	srv := &Client{
 		Client:        mqtt.NewClient(opts),
 		subscriptions: &sync.Map{},
+		log:           log.NewHelper(log.GetLogger()),
+	}
+	for _, o := range options {
+		o(srv)
 	}
 	return srv
 }
 
+// clientID 返回当前连接使用的 ClientID
+func (c *Client) clientID() string {
+	r := c.Client.OptionsReader()
+	return r.ClientID()
+}
+
 func (c *Client) GetSubscriptions() *sync.Map {
 	if c.subscriptions == nil {
 		return &sync.Map{}
@@ -37,10 +48,10 @@ func (c *Client) Subscribe(topic string, qos byte, callback mqtt.MessageHandler)
 	}
 
 	// 使用sync.Map存储订阅信息，避免频繁创建新的map
-	subscriptions, _ := c.subscriptions.LoadOrStore(c.Client.OptionsReader().ClientID, &sync.Map{})
+	subscriptions, _ := c.subscriptions.LoadOrStore(c.clientID(), &sync.Map{})
 	subscriptions.(*sync.Map).Store(topic, qos)
 
-	klog.Log.Infof("成功订阅主题: %s (QoS: %d)", topic, qos)
+	c.log.Infof("成功订阅主题: %s (QoS: %d)", topic, qos)
 	return nil
 }
 
@@ -53,12 +64,12 @@ func (c *Client) Unsubscribe(topic string) error {
 	}
 
 	// 使用sync.Map移除订阅信息
-	subscriptions, ok := c.subscriptions.Load(c.Client.OptionsReader().ClientID)
+	subscriptions, ok := c.subscriptions.Load(c.clientID())
 	if ok {
 		subscriptions.(*sync.Map).Delete(topic)
 	}
 
-	klog.Log.Infof("成功取消订阅主题: %s", topic)
+	c.log.Infof("成功取消订阅主题: %s", topic)
 	return nil
 }
 
@@ -70,6 +81,6 @@ func (c *Client) Publish(topic string, qos byte, retained bool, payload interfac
 		return token.Error() // 返回发布消息错误
 	}
 
-	klog.Log.Infof("成功发布消息至主题: %s", topic)
+	c.log.Infof("成功发布消息至主题: %s", topic)
 	return nil
 }
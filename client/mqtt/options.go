@@ -1,3 +1,12 @@
 package mqtt
 
+import "github.com/jiushengTech/common/log"
+
 type ClientOption func(o *Client)
+
+// WithLogger 设置日志记录器，默认使用 log.GetLogger()
+func WithLogger(l log.Logger) ClientOption {
+	return func(c *Client) {
+		c.log = log.NewHelper(l)
+	}
+}
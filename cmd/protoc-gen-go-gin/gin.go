@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	contextPackage = protogen.GoImportPath("context")
+	ginPackage     = protogen.GoImportPath("github.com/jiushengTech/common/transport/gin")
+)
+
+var (
+	pathVarRegexp = regexp.MustCompile(`{([^{}=]+)(=[^{}]*)?}`)
+	// methodSets counts the routes of every method, numbering the handlers of additional bindings
+	methodSets = make(map[string]int)
+)
+
+// generateFile generates a _gin.pb.go file containing the gin routes of the services.
+func generateFile(gen *protogen.Plugin, file *protogen.File, omitempty bool) *protogen.GeneratedFile {
+	if len(file.Services) == 0 || (omitempty && !hasHTTPRule(file.Services)) {
+		return nil
+	}
+	filename := file.GeneratedFilenamePrefix + "_gin.pb.go"
+	g := gen.NewGeneratedFile(filename, file.GoImportPath)
+	g.P("// Code generated by protoc-gen-go-gin. DO NOT EDIT.")
+	g.P("// versions:")
+	g.P(fmt.Sprintf("// - protoc-gen-go-gin %s", release))
+	g.P("// - protoc             ", protocVersion(gen))
+	if file.Proto.GetOptions().GetDeprecated() {
+		g.P("// ", file.Desc.Path(), " is a deprecated file.")
+	} else {
+		g.P("// source: ", file.Desc.Path())
+	}
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+	generateFileContent(gen, file, g, omitempty)
+	return g
+}
+
+func generateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, omitempty bool) {
+	g.P("// This is a compile-time assertion to ensure that this generated file")
+	g.P("// is compatible with the gin transport package it is being compiled against.")
+	g.P("var _ = new(", contextPackage.Ident("Context"), ")")
+	g.P("const _ = ", ginPackage.Ident("SupportPackageIsVersion1"))
+	g.P()
+	for _, service := range file.Services {
+		genService(gen, file, g, service, omitempty)
+	}
+}
+
+func genService(_ *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, service *protogen.Service, omitempty bool) {
+	if service.Desc.Options().(*descriptorpb.ServiceOptions).GetDeprecated() {
+		g.P("//")
+		g.P(deprecationComment)
+	}
+	sd := &serviceDesc{
+		ServiceType: service.GoName,
+		ServiceName: string(service.Desc.FullName()),
+		Metadata:    file.Desc.Path(),
+	}
+	for _, method := range service.Methods {
+		if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+			continue
+		}
+		rule, ok := proto.GetExtension(method.Desc.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if rule != nil && ok {
+			for _, bind := range rule.AdditionalBindings {
+				sd.Methods = append(sd.Methods, buildHTTPRule(g, service, method, bind))
+			}
+			sd.Methods = append(sd.Methods, buildHTTPRule(g, service, method, rule))
+		} else if !omitempty {
+			path := fmt.Sprintf("/%s/%s", service.Desc.FullName(), method.Desc.Name())
+			sd.Methods = append(sd.Methods, buildMethodDesc(g, method, http.MethodPost, path))
+		}
+	}
+	if len(sd.Methods) != 0 {
+		g.P(sd.execute())
+	}
+}
+
+func hasHTTPRule(services []*protogen.Service) bool {
+	for _, service := range services {
+		for _, method := range service.Methods {
+			if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+				continue
+			}
+			rule, ok := proto.GetExtension(method.Desc.Options(), annotations.E_Http).(*annotations.HttpRule)
+			if rule != nil && ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func buildHTTPRule(g *protogen.GeneratedFile, service *protogen.Service, m *protogen.Method, rule *annotations.HttpRule) *methodDesc {
+	var path, method string
+	switch pattern := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		path, method = pattern.Get, http.MethodGet
+	case *annotations.HttpRule_Put:
+		path, method = pattern.Put, http.MethodPut
+	case *annotations.HttpRule_Post:
+		path, method = pattern.Post, http.MethodPost
+	case *annotations.HttpRule_Delete:
+		path, method = pattern.Delete, http.MethodDelete
+	case *annotations.HttpRule_Patch:
+		path, method = pattern.Patch, http.MethodPatch
+	case *annotations.HttpRule_Custom:
+		path, method = pattern.Custom.Path, pattern.Custom.Kind
+	}
+	md := buildMethodDesc(g, m, method, path)
+	if method == http.MethodGet || method == http.MethodDelete {
+		if rule.Body != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mWARN\u001B[m: %s %s body should not be declared.\n", method, path)
+		}
+	} else if rule.Body == "" {
+		_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mWARN\u001B[m: %s %s does not declare a body.\n", method, path)
+	}
+	switch rule.Body {
+	case "":
+	case "*":
+		md.HasBody = true
+	default:
+		md.HasBody = true
+		md.Body = "." + goFieldPath(service, m.Input, rule.Body)
+	}
+	if rule.ResponseBody != "" {
+		md.ResponseBody = "." + goFieldPath(service, m.Output, rule.ResponseBody)
+	}
+	return md
+}
+
+func buildMethodDesc(g *protogen.GeneratedFile, m *protogen.Method, method, path string) *methodDesc {
+	defer func() { methodSets[m.GoName]++ }()
+
+	vars := pathVars(path)
+	for _, v := range vars {
+		msg := m.Input.Desc
+		for _, name := range strings.Split(v, ".") {
+			var fd protoreflect.FieldDescriptor
+			if msg != nil {
+				fd = msg.Fields().ByName(protoreflect.Name(name))
+			}
+			if fd == nil {
+				_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mERROR\u001B[m: The corresponding field '%s' declaration in message could not be found in '%s'\n", v, path)
+				os.Exit(2)
+			}
+			msg = fd.Message()
+		}
+	}
+	comment := m.Comments.Leading.String() + m.Comments.Trailing.String()
+	if comment != "" {
+		comment = "// " + m.GoName + strings.TrimPrefix(strings.TrimSuffix(comment, "\n"), "//")
+	}
+	return &methodDesc{
+		Name:         m.GoName,
+		OriginalName: string(m.Desc.Name()),
+		Num:          methodSets[m.GoName],
+		Request:      g.QualifiedGoIdent(m.Input.GoIdent),
+		Reply:        g.QualifiedGoIdent(m.Output.GoIdent),
+		Comment:      comment,
+		Path:         ginPath(path),
+		Method:       method,
+		HasVars:      len(vars) > 0,
+	}
+}
+
+// pathVars returns the field paths of the variables in the path template.
+func pathVars(path string) []string {
+	var vars []string
+	for _, m := range pathVarRegexp.FindAllStringSubmatch(path, -1) {
+		vars = append(vars, m[1])
+	}
+	return vars
+}
+
+// ginPath converts the path template to a gin path: {id} is converted to :id, and a variable with
+// a pattern, like {name=shelves/*/books/*}, to the catch-all *name when it is the last segment.
+func ginPath(path string) string {
+	return pathVarRegexp.ReplaceAllStringFunc(path, func(s string) string {
+		m := pathVarRegexp.FindStringSubmatch(s)
+		if m[2] == "" {
+			return ":" + m[1]
+		}
+		if strings.HasSuffix(path, s) {
+			return "*" + m[1]
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mWARN\u001B[m: the pattern of %s in %s is not supported by gin, matching a single segment.\n", m[1], path)
+		return ":" + m[1]
+	})
+}
+
+// goFieldPath converts the proto field path of the message to the Go field path.
+func goFieldPath(service *protogen.Service, msg *protogen.Message, path string) string {
+	var names []string
+	for _, name := range strings.Split(path, ".") {
+		var field *protogen.Field
+		for _, f := range msg.Fields {
+			if string(f.Desc.Name()) == name {
+				field = f
+				break
+			}
+		}
+		if field == nil {
+			_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mERROR\u001B[m: The field '%s' in %s of service %s could not be found\n", path, msg.Desc.FullName(), service.Desc.FullName())
+			os.Exit(2)
+		}
+		names = append(names, field.GoName)
+		msg = field.Message
+	}
+	return strings.Join(names, ".")
+}
+
+func protocVersion(gen *protogen.Plugin) string {
+	v := gen.Request.GetCompilerVersion()
+	if v == nil {
+		return "(unknown)"
+	}
+	var suffix string
+	if s := v.GetSuffix(); s != "" {
+		suffix = "-" + s
+	}
+	return fmt.Sprintf("v%d.%d.%d%s", v.GetMajor(), v.GetMinor(), v.GetPatch(), suffix)
+}
+
+const deprecationComment = "// Deprecated: Do not use."
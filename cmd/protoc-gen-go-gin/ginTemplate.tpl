@@ -0,0 +1,58 @@
+{{$svrType := .ServiceType}}
+{{$svrName := .ServiceName}}
+
+{{- range .MethodSets}}
+const Operation{{$svrType}}{{.OriginalName}} = "/{{$svrName}}/{{.OriginalName}}"
+{{- end}}
+
+type {{.ServiceType}}GinServer interface {
+{{- range .MethodSets}}
+	{{- if ne .Comment ""}}
+	{{.Comment}}
+	{{- end}}
+	{{.Name}}(context.Context, *{{.Request}}) (*{{.Reply}}, error)
+{{- end}}
+}
+
+func Register{{.ServiceType}}GinServer(s *gin.Server, srv {{.ServiceType}}GinServer) {
+	{{- range .Methods}}
+	s.Engine.Handle("{{.Method}}", "{{.Path}}", s.Handle(_{{$svrType}}_{{.Name}}{{.Num}}_Gin_Handler(srv)))
+	{{- end}}
+}
+
+{{range .Methods}}
+func _{{$svrType}}_{{.Name}}{{.Num}}_Gin_Handler(srv {{$svrType}}GinServer) gin.HandlerFunc {
+	return func(ctx *gin.Context) error {
+		var in {{.Request}}
+		{{- if .HasBody}}
+		if err := ctx.Bind(&in{{.Body}}); err != nil {
+			return err
+		}
+		{{- if not (eq .Body "")}}
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		{{- end}}
+		{{- else}}
+		if err := ctx.BindQuery(&in{{.Body}}); err != nil {
+			return err
+		}
+		{{- end}}
+		{{- if .HasVars}}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		{{- end}}
+		gin.SetOperation(ctx, Operation{{$svrType}}{{.OriginalName}})
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.{{.Name}}(ctx, req.(*{{.Request}}))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*{{.Reply}})
+		return ctx.Result(200, reply{{.ResponseBody}})
+	}
+}
+{{end}}
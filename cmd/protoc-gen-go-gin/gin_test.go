@@ -0,0 +1,27 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGinPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		vars []string
+	}{
+		{"/hygrothermograph", "/hygrothermograph", nil},
+		{"/hygrothermograph/{id}", "/hygrothermograph/:id", []string{"id"}},
+		{"/v1/{parent.id}/books/{book_id}", "/v1/:parent.id/books/:book_id", []string{"parent.id", "book_id"}},
+		{"/v1/{name=shelves/*/books/*}", "/v1/*name", []string{"name"}},
+	}
+	for _, tt := range tests {
+		if got := ginPath(tt.path); got != tt.want {
+			t.Errorf("ginPath(%s): expect %s, got %s", tt.path, tt.want, got)
+		}
+		if got := pathVars(tt.path); !reflect.DeepEqual(got, tt.vars) {
+			t.Errorf("pathVars(%s): expect %v, got %v", tt.path, tt.vars, got)
+		}
+	}
+}
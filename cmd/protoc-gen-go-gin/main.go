@@ -0,0 +1,43 @@
+// protoc-gen-go-gin generates gin routes of the services annotated by google.api.http,
+// registered on github.com/jiushengTech/common/transport/gin.Server.
+//
+//	protoc --proto_path=. --proto_path=./third_party \
+//	       --go_out=paths=source_relative:. \
+//	       --go-gin_out=paths=source_relative:. \
+//	       api/helloworld/v1/greeter.proto
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const release = "v1.0.0"
+
+var (
+	showVersion = flag.Bool("version", false, "print the version and exit")
+	omitempty   = flag.Bool("omitempty", true, "omit the methods without google.api.http, or route them to POST /package.Service/Method")
+)
+
+func main() {
+	flag.Parse()
+	if *showVersion {
+		fmt.Printf("protoc-gen-go-gin %v\n", release)
+		return
+	}
+	protogen.Options{
+		ParamFunc: flag.CommandLine.Set,
+	}.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, f := range gen.Files {
+			if !f.Generate {
+				continue
+			}
+			generateFile(gen, f, *omitempty)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,157 @@
+// Package nacos is a kratos config source on top of the nacos config client.
+//
+//	source := nacos.NewConfigSource(client,
+//		nacos.WithGroup("app"),
+//		nacos.WithDataID("app.yaml", "log.properties"),
+//		nacos.WithItem(nacos.Item{DataID: "common", Group: "shared", Format: nacos.FormatJSON}),
+//	)
+//	c := config.New(config.WithSource(source))
+package nacos
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	klog "github.com/go-kratos/kratos/v2/log"
+	"github.com/jiushengTech/common/log"
+	"github.com/jiushengTech/kratos/v2/config"
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+var ErrNoDataID = errors.New("kratos/nacos: no dataId to load")
+
+var _ config.Source = (*Source)(nil)
+
+// Item is a nacos config identified by dataId and group.
+type Item struct {
+	DataID string
+	// Group of the config, defaults to the group of WithGroup.
+	Group string
+	// Format of the config, detected by the extension of DataID or the content when empty.
+	Format string
+}
+
+type options struct {
+	group  string
+	items  []Item
+	logger log.Logger
+}
+
+// Option is nacos config option.
+type Option func(o *options)
+
+// WithGroup with default group option.
+func WithGroup(group string) Option {
+	return func(o *options) { o.group = group }
+}
+
+// WithDataID with dataId option, the configs are in the default group.
+func WithDataID(dataIDs ...string) Option {
+	return func(o *options) {
+		for _, id := range dataIDs {
+			o.items = append(o.items, Item{DataID: id})
+		}
+	}
+}
+
+// WithItem with config item option.
+func WithItem(items ...Item) Option {
+	return func(o *options) { o.items = append(o.items, items...) }
+}
+
+// WithLogger with logger option.
+func WithLogger(logger log.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// Source is nacos config source.
+// The configs are loaded in order, later configs override earlier ones.
+type Source struct {
+	items []Item
+	cli   config_client.IConfigClient
+	log   *klog.Helper
+
+	mu       sync.Mutex
+	contents map[int]string // item index -> latest content
+}
+
+// NewConfigSource new a nacos config source.
+func NewConfigSource(cli config_client.IConfigClient, opts ...Option) *Source {
+	op := options{
+		group:  constant.DEFAULT_GROUP,
+		logger: log.GetLogger(),
+	}
+	for _, option := range opts {
+		option(&op)
+	}
+	items := make([]Item, 0, len(op.items))
+	for _, it := range op.items {
+		if it.Group == "" {
+			it.Group = op.group
+		}
+		items = append(items, it)
+	}
+	return &Source{
+		items:    items,
+		cli:      cli,
+		log:      log.NewHelper(op.logger),
+		contents: make(map[int]string, len(items)),
+	}
+}
+
+// Load loads all the configs.
+func (s *Source) Load() ([]*config.KeyValue, error) {
+	if len(s.items) == 0 {
+		return nil, ErrNoDataID
+	}
+	kvs := make([]*config.KeyValue, 0, len(s.items))
+	contents := make(map[int]string, len(s.items))
+	for i, it := range s.items {
+		content, err := s.cli.GetConfig(vo.ConfigParam{
+			DataId: it.DataID,
+			Group:  it.Group,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("nacos: get config %s/%s err: %w", it.Group, it.DataID, err)
+		}
+		kv, err := newKeyValue(it, content)
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = content
+		kvs = append(kvs, kv)
+	}
+	s.mu.Lock()
+	s.contents = contents
+	s.mu.Unlock()
+	return kvs, nil
+}
+
+// Watch watches changes of all the configs.
+func (s *Source) Watch() (config.Watcher, error) {
+	return newWatcher(s)
+}
+
+// newKeyValue converts the content of a config, properties are converted to json.
+func newKeyValue(it Item, content string) (*config.KeyValue, error) {
+	format := it.Format
+	if format == "" {
+		format = detectFormat(it.DataID, content)
+	}
+	value := []byte(content)
+	if format == FormatProperties {
+		b, err := propertiesToJSON(content)
+		if err != nil {
+			return nil, fmt.Errorf("nacos: parse properties %s/%s err: %w", it.Group, it.DataID, err)
+		}
+		value, format = b, FormatJSON
+	}
+	return &config.KeyValue{
+		Key:    it.DataID,
+		Value:  value,
+		Format: format,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package nacos
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+var _ config_client.IConfigClient = (*fakeConfigClient)(nil)
+
+var errFakeNotFound = errors.New("fake nacos: config not found")
+
+// fakeConfigClient is an in-memory IConfigClient.
+type fakeConfigClient struct {
+	config_client.IConfigClient
+
+	mu        sync.Mutex
+	configs   map[string]string // group/dataId -> content
+	listeners map[string]func(namespace, group, dataId, data string)
+}
+
+func newFakeConfigClient() *fakeConfigClient {
+	return &fakeConfigClient{
+		configs:   make(map[string]string),
+		listeners: make(map[string]func(namespace, group, dataId, data string)),
+	}
+}
+
+func configKey(group, dataID string) string {
+	return group + "/" + dataID
+}
+
+func (c *fakeConfigClient) GetConfig(param vo.ConfigParam) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	content, ok := c.configs[configKey(param.Group, param.DataId)]
+	if !ok {
+		return "", errFakeNotFound
+	}
+	return content, nil
+}
+
+// PublishConfig stores the config and calls the listener like nacos does.
+func (c *fakeConfigClient) PublishConfig(param vo.ConfigParam) (bool, error) {
+	key := configKey(param.Group, param.DataId)
+	c.mu.Lock()
+	c.configs[key] = param.Content
+	l := c.listeners[key]
+	c.mu.Unlock()
+	if l != nil {
+		l("", param.Group, param.DataId, param.Content)
+	}
+	return true, nil
+}
+
+func (c *fakeConfigClient) ListenConfig(param vo.ConfigParam) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners[configKey(param.Group, param.DataId)] = param.OnChange
+	return nil
+}
+
+func (c *fakeConfigClient) CancelListenConfig(param vo.ConfigParam) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.listeners, configKey(param.Group, param.DataId))
+	return nil
+}
+
+func (c *fakeConfigClient) listening() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.listeners)
+}
+
+func (c *fakeConfigClient) publish(group, dataID, content string) {
+	_, _ = c.PublishConfig(vo.ConfigParam{DataId: dataID, Group: group, Content: content})
+}
@@ -0,0 +1,162 @@
+package nacos
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jiushengTech/kratos/v2/config"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		dataID  string
+		content string
+		want    string
+	}{
+		{name: "yamlExt", dataID: "app.yml", content: `{"a":1}`, want: FormatYAML},
+		{name: "jsonExt", dataID: "app.JSON", want: FormatJSON},
+		{name: "propertiesExt", dataID: "app.properties", want: FormatProperties},
+		{name: "jsonContent", dataID: "app", content: " {\"a\": 1}", want: FormatJSON},
+		{name: "propertiesContent", dataID: "app", content: "# comment\na.b=1\nc = x:y", want: FormatProperties},
+		{name: "yamlContent", dataID: "app", content: "a:\n  b: x=y", want: FormatYAML},
+		{name: "empty", dataID: "app", want: FormatYAML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectFormat(tt.dataID, tt.content); got != tt.want {
+				t.Errorf("expect %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPropertiesToJSON(t *testing.T) {
+	b, err := propertiesToJSON("! comment\nserver.http.addr=0.0.0.0:8000\nserver.http.timeout: 1s\nname = long \\\n  value\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"long value","server":{"http":{"addr":"0.0.0.0:8000","timeout":"1s"}}}`
+	if string(b) != want {
+		t.Errorf("expect %s, got %s", want, b)
+	}
+}
+
+type testConf struct {
+	Server struct {
+		Addr string `json:"addr"`
+		Port int    `json:"port"`
+	} `json:"server"`
+	Name string `json:"name"`
+}
+
+func TestSource(t *testing.T) {
+	cli := newFakeConfigClient()
+	cli.publish("app", "server.yaml", "server:\n  addr: 0.0.0.0\n  port: 8000\nname: yaml")
+	cli.publish("shared", "override", `{"server":{"port":9000}}`)
+	cli.publish("app", "name.properties", "name=properties")
+
+	src := NewConfigSource(cli,
+		WithGroup("app"),
+		WithDataID("server.yaml"),
+		WithItem(Item{DataID: "override", Group: "shared"}),
+		WithDataID("name.properties"),
+	)
+	c := config.New(config.WithSource(src))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+
+	var conf testConf
+	if err := c.Scan(&conf); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Server.Addr != "0.0.0.0" || conf.Server.Port != 9000 || conf.Name != "properties" {
+		t.Fatalf("unexpected config %+v", conf)
+	}
+
+	changed := make(chan int64, 1)
+	if err := c.Watch("server.port", func(_ string, v config.Value) {
+		p, _ := v.Int()
+		changed <- p
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the override still wins after the earlier config changes
+	cli.publish("app", "server.yaml", "server:\n  addr: 127.0.0.1\n  port: 8001\nname: yaml")
+	waitValue(t, c, "server.addr", "127.0.0.1")
+	if port, _ := c.Value("server.port").Int(); port != 9000 {
+		t.Errorf("expect port 9000, got %d", port)
+	}
+
+	cli.publish("shared", "override", `{"server":{"port":9001}}`)
+	select {
+	case p := <-changed:
+		if p != 9001 {
+			t.Errorf("expect port 9001, got %d", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("observer not called after config changed")
+	}
+}
+
+// waitValue waits until the config value of key is want.
+func waitValue(t *testing.T, c config.Config, key, want string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if s, _ := c.Value(key).String(); s == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expect %s=%s before timeout", key, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSource_Errors(t *testing.T) {
+	cli := newFakeConfigClient()
+	if _, err := NewConfigSource(cli).Load(); !errors.Is(err, ErrNoDataID) {
+		t.Errorf("expect ErrNoDataID, got %v", err)
+	}
+	if _, err := NewConfigSource(cli, WithDataID("missing.yaml")).Load(); !errors.Is(err, errFakeNotFound) {
+		t.Errorf("expect errFakeNotFound, got %v", err)
+	}
+}
+
+func TestWatcher_Stop(t *testing.T) {
+	cli := newFakeConfigClient()
+	cli.publish("DEFAULT_GROUP", "a.yaml", "a: 1")
+	src := NewConfigSource(cli, WithDataID("a.yaml"))
+	if _, err := src.Load(); err != nil {
+		t.Fatal(err)
+	}
+	w, err := src.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cli.listening() != 1 {
+		t.Fatalf("expect 1 listener, got %d", cli.listening())
+	}
+	cli.publish("DEFAULT_GROUP", "a.yaml", "a: 2")
+	kvs, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kvs) != 1 || string(kvs[0].Value) != "a: 2" || kvs[0].Format != FormatYAML {
+		t.Errorf("unexpected key values %+v", kvs)
+	}
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if cli.listening() != 0 {
+		t.Errorf("expect listeners canceled, got %d", cli.listening())
+	}
+	if _, err = w.Next(); err == nil {
+		t.Errorf("expect error after Stop")
+	}
+}
@@ -0,0 +1,103 @@
+package nacos
+
+import (
+	"bufio"
+	"encoding/json"
+	"path"
+	"strings"
+)
+
+// Formats of nacos configs, yaml and json are the names of kratos codecs.
+const (
+	FormatYAML       = "yaml"
+	FormatJSON       = "json"
+	FormatProperties = "properties"
+)
+
+// detectFormat detects the format by the extension of dataId, then by the content.
+func detectFormat(dataID, content string) string {
+	switch strings.ToLower(strings.TrimPrefix(path.Ext(dataID), ".")) {
+	case "yaml", "yml":
+		return FormatYAML
+	case "json":
+		return FormatJSON
+	case "properties", "props":
+		return FormatProperties
+	}
+
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return FormatJSON
+	}
+	if isProperties(trimmed) {
+		return FormatProperties
+	}
+	return FormatYAML
+}
+
+// isProperties reports whether every non-comment line is key=value.
+func isProperties(content string) bool {
+	found := false
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		eq := strings.Index(line, "=")
+		if eq <= 0 {
+			return false
+		}
+		// "key: a=b" is yaml
+		if colon := strings.Index(line, ":"); colon >= 0 && colon < eq {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// propertiesToJSON converts properties to json, keys are expanded by dots.
+// "a.b=1" becomes {"a":{"b":"1"}}.
+func propertiesToJSON(content string) ([]byte, error) {
+	root := make(map[string]interface{})
+	sc := bufio.NewScanner(strings.NewReader(content))
+	var pending string
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if pending == "" && (line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!")) {
+			continue
+		}
+		// a trailing backslash continues the line
+		if strings.HasSuffix(line, `\`) {
+			pending += strings.TrimSuffix(line, `\`)
+			continue
+		}
+		line, pending = pending+line, ""
+
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			setValue(root, line, "")
+			continue
+		}
+		setValue(root, strings.TrimSpace(line[:sep]), strings.TrimSpace(line[sep+1:]))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+func setValue(root map[string]interface{}, key, value string) {
+	keys := strings.Split(key, ".")
+	m := root
+	for _, k := range keys[:len(keys)-1] {
+		sub, ok := m[k].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			m[k] = sub
+		}
+		m = sub
+	}
+	m[keys[len(keys)-1]] = value
+}
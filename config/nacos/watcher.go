@@ -0,0 +1,93 @@
+package nacos
+
+import (
+	"context"
+
+	"github.com/jiushengTech/kratos/v2/config"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+var _ config.Watcher = (*watcher)(nil)
+
+type watcher struct {
+	source *Source
+	ctx    context.Context
+	cancel context.CancelFunc
+	notify chan struct{}
+}
+
+func newWatcher(s *Source) (*watcher, error) {
+	w := &watcher{
+		source: s,
+		notify: make(chan struct{}, 1),
+	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	for i, it := range s.items {
+		i := i
+		err := s.cli.ListenConfig(vo.ConfigParam{
+			DataId: it.DataID,
+			Group:  it.Group,
+			OnChange: func(_, _, _, data string) {
+				w.onChange(i, data)
+			},
+		})
+		if err != nil {
+			_ = w.Stop()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// onChange records the latest content, changes before Next are merged.
+func (w *watcher) onChange(i int, data string) {
+	w.source.mu.Lock()
+	w.source.contents[i] = data
+	w.source.mu.Unlock()
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Next returns all the configs in order once any of them changes,
+// so that later configs still override earlier ones after merging.
+func (w *watcher) Next() ([]*config.KeyValue, error) {
+	select {
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	case <-w.notify:
+	}
+
+	w.source.mu.Lock()
+	defer w.source.mu.Unlock()
+	kvs := make([]*config.KeyValue, 0, len(w.source.items))
+	for i, it := range w.source.items {
+		data, ok := w.source.contents[i]
+		if !ok {
+			continue
+		}
+		kv, err := newKeyValue(it, data)
+		if err != nil {
+			w.source.log.Errorf("nacos: config %s/%s changed with invalid content: %v", it.Group, it.DataID, err)
+			continue
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs, nil
+}
+
+// Stop cancels listening all the configs.
+func (w *watcher) Stop() error {
+	w.cancel()
+	var err error
+	for _, it := range w.source.items {
+		if e := w.source.cli.CancelListenConfig(vo.ConfigParam{
+			DataId: it.DataID,
+			Group:  it.Group,
+		}); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"time"
+
+	klog "github.com/go-kratos/kratos/v2/log"
+	"github.com/jiushengTech/common/log"
 )
 
 type HttpClient struct {
@@ -19,6 +21,7 @@ type HttpClient struct {
 	redirectNum int           // HTTP重定向次数限制
 	retryCount  int           // 请求重试次数
 	retryDelay  time.Duration // 重试间隔时间
+	log         *klog.Helper
 }
 
 func (c *HttpClient) getClient() *http.Client {
@@ -44,6 +47,7 @@ func NewHttpClient(opts ...Option) *HttpClient {
 		redirectNum: 3,                // 默认重定向次数限制
 		retryCount:  0,                // 默认不重试
 		retryDelay:  1 * time.Second,  // 默认重试间隔1秒
+		log:         log.NewHelper(log.GetLogger()),
 	}
 	for _, o := range opts {
 		o(srv)
@@ -60,7 +64,7 @@ func (c *HttpClient) doRequestWithRetry(req *http.Request) (*http.Response, erro
 		if attempt > 0 {
 			// 等待重试间隔
 			time.Sleep(c.retryDelay)
-			log.Printf("重试第 %d 次请求: %s %s", attempt, req.Method, req.URL.String())
+			c.log.Warnf("重试第 %d 次请求: %s %s", attempt, req.Method, req.URL.String())
 		}
 
 		resp, err := client.Do(req)
@@ -112,7 +116,7 @@ func (c *HttpClient) Get(url string) ([]byte, error) {
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
-			log.Printf("关闭响应体失败: %v", closeErr)
+			c.log.Warnf("关闭响应体失败: %v", closeErr)
 		}
 	}()
 
@@ -173,7 +177,7 @@ func (c *HttpClient) PostJSON(url string) ([]byte, error) {
 	}
 	defer func() {
 		if closeErr := response.Body.Close(); closeErr != nil {
-			log.Printf("关闭响应体失败: %v", closeErr)
+			c.log.Warnf("关闭响应体失败: %v", closeErr)
 		}
 	}()
 
@@ -235,7 +239,7 @@ func (c *HttpClient) PostForm(url string, formData map[string]string) ([]byte, e
 	}
 	defer func() {
 		if closeErr := response.Body.Close(); closeErr != nil {
-			log.Printf("关闭响应体失败: %v", closeErr)
+			c.log.Warnf("关闭响应体失败: %v", closeErr)
 		}
 	}()
 
@@ -297,7 +301,7 @@ func (c *HttpClient) PutJSON(url string) ([]byte, error) {
 	}
 	defer func() {
 		if closeErr := response.Body.Close(); closeErr != nil {
-			log.Printf("关闭响应体失败: %v", closeErr)
+			c.log.Warnf("关闭响应体失败: %v", closeErr)
 		}
 	}()
 
@@ -349,7 +353,7 @@ func (c *HttpClient) Delete(url string) ([]byte, error) {
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
-			log.Printf("关闭响应体失败: %v", closeErr)
+			c.log.Warnf("关闭响应体失败: %v", closeErr)
 		}
 	}()
 
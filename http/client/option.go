@@ -1,6 +1,10 @@
 package client
 
-import "time"
+import (
+	"time"
+
+	"github.com/jiushengTech/common/log"
+)
 
 type Option func(o *HttpClient)
 
@@ -42,3 +46,10 @@ func WithRetryDelay(retryDelay time.Duration) Option {
 		s.retryDelay = retryDelay
 	}
 }
+
+// WithLogger 设置日志记录器，默认使用 log.GetLogger()
+func WithLogger(l log.Logger) Option {
+	return func(s *HttpClient) {
+		s.log = log.NewHelper(l)
+	}
+}
@@ -0,0 +1,15 @@
+// Package datedir 提供按日期划分的目录，供 transport/gin 的上传和 utils/pathutil 共用。
+//
+// transport/gin 所在的根模块不依赖 utils 模块（utils 依赖根模块，反向依赖会形成模块循环），
+// 因此日期目录的规则放在这里，由两边共同引用，避免两份实现不一致。
+package datedir
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Join 在 base 下拼接当前日期目录 (YYYY-MM-DD)，base 为空时只返回日期
+func Join(base string) string {
+	return filepath.Join(base, time.Now().Format(time.DateOnly))
+}
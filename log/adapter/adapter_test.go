@@ -0,0 +1,85 @@
+package adapter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewZapCore(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	l := zap.New(NewZapCore(h)).Named("mqtt").With(zap.String("client", "c1"))
+
+	l.Debug("ignored")
+	l.Info("hello", zap.Int("qos", 1))
+
+	out := buf.String()
+	if strings.Contains(out, "ignored") {
+		t.Errorf("debug log should be filtered, got %q", out)
+	}
+	for _, want := range []string{"msg=hello", "client=c1", "logger=mqtt", "qos=1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expect %q in %q", want, out)
+		}
+	}
+}
+
+func TestNewKratosLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := NewKratosLogger(zap.New(core))
+
+	log.NewHelper(l).Infow("msg", "hello", "topic", "a/b")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, got %d", len(entries))
+	}
+	if entries[0].Message != "hello" {
+		t.Errorf("expect message %q, got %q", "hello", entries[0].Message)
+	}
+	if v := entries[0].ContextMap()["topic"]; v != "a/b" {
+		t.Errorf("expect topic %q, got %v", "a/b", v)
+	}
+}
+
+type recordLogger struct {
+	level   log.Level
+	keyvals []any
+}
+
+func (r *recordLogger) Log(level log.Level, keyvals ...any) error {
+	r.level = level
+	r.keyvals = keyvals
+	return nil
+}
+
+func TestNewSlogHandler(t *testing.T) {
+	r := &recordLogger{}
+	l := slog.New(NewSlogHandler(r, WithSlogLevel(slog.LevelInfo)))
+
+	l.Debug("ignored")
+	if r.keyvals != nil {
+		t.Fatalf("debug log should be filtered, got %v", r.keyvals)
+	}
+
+	l.With("app", "demo").WithGroup("req").Warn("slow", "cost", 3, slog.Group("user", "id", 7))
+	if r.level != log.LevelWarn {
+		t.Errorf("expect level %v, got %v", log.LevelWarn, r.level)
+	}
+	want := []any{"msg", "slow", "app", "demo", "req.cost", int64(3), "req.user.id", int64(7)}
+	if len(r.keyvals) != len(want) {
+		t.Fatalf("expect %v, got %v", want, r.keyvals)
+	}
+	for i := range want {
+		if r.keyvals[i] != want[i] {
+			t.Errorf("keyvals[%d]: expect %v, got %v", i, want[i], r.keyvals[i])
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package adapter
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+var _ slog.Handler = (*kratosHandler)(nil)
+
+// kratosHandler 将 slog 的日志记录转发给 kratos log.Logger
+type kratosHandler struct {
+	logger log.Logger
+	level  slog.Leveler
+	attrs  []any  // WithAttrs 附加的键值对
+	group  string // WithGroup 产生的键前缀，形如 "a.b."
+}
+
+// SlogOption NewSlogHandler 的配置项
+type SlogOption func(*kratosHandler)
+
+// WithSlogLevel 设置 slog.Handler 的最低日志级别，默认 slog.LevelDebug
+func WithSlogLevel(level slog.Leveler) SlogOption {
+	return func(h *kratosHandler) {
+		h.level = level
+	}
+}
+
+// NewSlogHandler 将 kratos log.Logger 转换为 slog.Handler
+// slog 的消息以 msg 键输出，分组属性的键以 "." 连接
+func NewSlogHandler(l log.Logger, opts ...SlogOption) slog.Handler {
+	h := &kratosHandler{
+		logger: l,
+		level:  slog.LevelDebug,
+	}
+	for _, o := range opts {
+		o(h)
+	}
+	return h
+}
+
+// Enabled 实现 slog.Handler 接口
+func (h *kratosHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle 实现 slog.Handler 接口
+func (h *kratosHandler) Handle(_ context.Context, r slog.Record) error {
+	keyvals := make([]any, 0, 2+len(h.attrs)+r.NumAttrs()*2)
+	keyvals = append(keyvals, log.DefaultMessageKey, r.Message)
+	keyvals = append(keyvals, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		keyvals = appendAttr(keyvals, h.group, a)
+		return true
+	})
+	return h.logger.Log(slogToKratosLevel(r.Level), keyvals...)
+}
+
+// WithAttrs 实现 slog.Handler 接口
+func (h *kratosHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	nh := h.clone()
+	for _, a := range attrs {
+		nh.attrs = appendAttr(nh.attrs, h.group, a)
+	}
+	return nh
+}
+
+// WithGroup 实现 slog.Handler 接口
+func (h *kratosHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := h.clone()
+	nh.group = h.group + name + "."
+	return nh
+}
+
+func (h *kratosHandler) clone() *kratosHandler {
+	attrs := make([]any, len(h.attrs), len(h.attrs)+2)
+	copy(attrs, h.attrs)
+	return &kratosHandler{
+		logger: h.logger,
+		level:  h.level,
+		attrs:  attrs,
+		group:  h.group,
+	}
+}
+
+// appendAttr 展开 slog 属性并追加到 keyvals，分组属性递归展开
+func appendAttr(keyvals []any, prefix string, a slog.Attr) []any {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return keyvals
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return keyvals
+		}
+		if a.Key != "" {
+			prefix = prefix + a.Key + "."
+		}
+		for _, ga := range group {
+			keyvals = appendAttr(keyvals, prefix, ga)
+		}
+		return keyvals
+	}
+	return append(keyvals, strings.TrimSuffix(prefix+a.Key, "."), a.Value.Any())
+}
+
+// slogToKratosLevel 将 slog 日志级别映射为 kratos 日志级别
+func slogToKratosLevel(level slog.Level) log.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return log.LevelDebug
+	case level < slog.LevelWarn:
+		return log.LevelInfo
+	case level < slog.LevelError:
+		return log.LevelWarn
+	default:
+		return log.LevelError
+	}
+}
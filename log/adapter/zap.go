@@ -0,0 +1,18 @@
+// Package adapter 提供 zap、slog 与 kratos log 之间的互相转换
+//
+//   - NewZapCore: slog.Handler -> zapcore.Core，让 zap.Logger 输出到任意 slog.Handler
+//   - NewKratosLogger: zap.Logger -> kratos log.Logger
+//   - NewSlogHandler: kratos log.Logger -> slog.Handler
+package adapter
+
+import (
+	"github.com/go-kratos/kratos/v2/log"
+	klogger "github.com/jiushengTech/common/log/klog/logger"
+	"go.uber.org/zap"
+)
+
+// NewKratosLogger 将 zap.Logger 转换为 kratos log.Logger
+// keyvals 中的 msg 字段会作为 zap 的日志消息，其余键值对转换为 zap 字段
+func NewKratosLogger(l *zap.Logger) log.Logger {
+	return klogger.NewFromZap(l)
+}
@@ -0,0 +1,97 @@
+package adapter
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var _ zapcore.Core = (*slogCore)(nil)
+
+// slogCore 将 zap 的日志写入 slog.Handler
+type slogCore struct {
+	handler slog.Handler
+}
+
+// NewZapCore 将 slog.Handler 转换为 zapcore.Core
+// 日志级别由 handler.Enabled 决定，zap 的 logger 名称以 logger 属性输出
+func NewZapCore(h slog.Handler) zapcore.Core {
+	return &slogCore{handler: h}
+}
+
+// Enabled 实现 zapcore.LevelEnabler 接口
+func (c *slogCore) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(context.Background(), zapToSlogLevel(level))
+}
+
+// With 为 Core 附加字段
+func (c *slogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slogCore{
+		handler: c.handler.WithAttrs(fieldsToAttrs(fields)),
+	}
+}
+
+// Check 判断是否需要记录该条日志
+func (c *slogCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+// Write 将日志写入 slog.Handler
+func (c *slogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	var pc uintptr
+	if entry.Caller.Defined {
+		pc = entry.Caller.PC
+	}
+	r := slog.NewRecord(entry.Time, zapToSlogLevel(entry.Level), entry.Message, pc)
+	if entry.LoggerName != "" {
+		r.AddAttrs(slog.String("logger", entry.LoggerName))
+	}
+	r.AddAttrs(fieldsToAttrs(fields)...)
+	if entry.Stack != "" {
+		r.AddAttrs(slog.String("stack", entry.Stack))
+	}
+	return c.handler.Handle(context.Background(), r)
+}
+
+// Sync slog.Handler 没有刷新语义，直接返回
+func (c *slogCore) Sync() error {
+	return nil
+}
+
+// fieldsToAttrs 将 zap 字段转换为 slog 属性
+func fieldsToAttrs(fields []zapcore.Field) []slog.Attr {
+	if len(fields) == 0 {
+		return nil
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	attrs := make([]slog.Attr, 0, len(enc.Fields))
+	// 按字段顺序输出，保证日志内容稳定
+	for _, f := range fields {
+		if v, ok := enc.Fields[f.Key]; ok {
+			attrs = append(attrs, slog.Any(f.Key, v))
+			delete(enc.Fields, f.Key)
+		}
+	}
+	return attrs
+}
+
+// zapToSlogLevel 将 zap 日志级别映射为 slog 日志级别
+func zapToSlogLevel(level zapcore.Level) slog.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return slog.LevelDebug
+	case level == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case level == zapcore.WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
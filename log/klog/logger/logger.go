@@ -17,42 +17,44 @@ type Logger struct {
 }
 
 var (
-	Log  *klog.Helper
+	// Log 全局日志 Helper，Setup 之前为 no-op，不会创建任何日志文件
+	Log  = klog.NewHelper(NewFromZap(zap.NewNop()))
 	once sync.Once
 )
 
+// Setup 根据配置初始化全局 Log，c 为 nil 时使用 Init 的默认配置
+func Setup(c *conf.ZapConf) {
+	if c == nil {
+		c = defaultConf()
+	}
+	Log = klog.NewHelper(NewLogger(c))
+}
+
+// Init 使用默认配置初始化全局 Log，多次调用只会生效一次
+// 需要由使用方显式调用，模块内的组件不会隐式调用
 func Init() {
 	once.Do(func() {
-		logger := z.NewZapLogger(&conf.ZapConf{
-			Model:         "dev",                        // 开发模式配置
-			Level:         "debug",                      // 日志级别设置为 debug（捕获 debug、info、warn、error 等）
-			Format:        "console",                    // 日志输出格式（console 或 JSON）
-			Director:      "logs",                       // 日志文件存储目录
-			EncodeLevel:   "LowercaseColorLevelEncoder", // 使用彩色小写级别名称在日志中
-			StacktraceKey: "stack",                      // 堆栈跟踪信息的 JSON 键名
-			MaxAge:        0,                            // 保留旧日志文件的最大天数（0 表示无限制）
-			AddCaller:     true,                         // 显示日志打印所在的行号
-			AddCallerSkip: 2,                            // 跳过调用栈的行数
-			LogInConsole:  true,                         // 是否在控制台输出日志
-			MaxSize:       10,                           // 每个日志文件的最大大小（单位：MB）
-			Compress:      true,                         // 是否压缩/归档旧日志文件
-			MaxBackups:    10,                           // 保留的旧日志文件的最大数量
-			TimeRotation:  z.RotateHourly,               // 时间轮转类型: "0:minute", "1:hour" 或 "2:day"
-		})
-		l := &Logger{
-			logger: logger,
-		}
-		Log = klog.NewHelper(l)
+		Setup(nil)
 	})
+}
 
+// defaultConf 默认配置，相比 z.DefaultZapConf 多跳过 kratos Helper 的两层调用栈
+func defaultConf() *conf.ZapConf {
+	c := z.DefaultZapConf()
+	c.AddCallerSkip = 2 // 跳过调用栈的行数
+	c.Compress = true   // 是否压缩/归档旧日志文件
+	return c
 }
 
 func NewLogger(c *conf.ZapConf) *Logger {
-	logger := z.NewZapLogger(c)
-	l := &Logger{
+	return NewFromZap(z.NewZapLogger(c))
+}
+
+// NewFromZap 使用已有的 zap.Logger 创建 klog.Logger
+func NewFromZap(logger *zap.Logger) *Logger {
+	return &Logger{
 		logger: logger,
 	}
-	return l
 }
 
 // Log 实现klog.Logger接口的Log方法
@@ -63,25 +65,30 @@ func (z *Logger) Log(level klog.Level, keyvals ...interface{}) error {
 		return nil
 	}
 
-	// 构建日志字段
+	// 构建日志字段，msg 字段作为 zap 的日志消息
+	var msg string
 	fields := make([]zap.Field, 0, len(keyvals)/2)
 	for i := 0; i < len(keyvals); i += 2 {
 		key := fmt.Sprint(keyvals[i])
+		if key == klog.DefaultMessageKey && msg == "" {
+			msg = fmt.Sprint(keyvals[i+1])
+			continue
+		}
 		fields = append(fields, zap.Any(key, keyvals[i+1]))
 	}
 
 	// 根据日志级别记录日志
 	switch level {
 	case klog.LevelDebug:
-		z.logger.Debug("", fields...)
+		z.logger.Debug(msg, fields...)
 	case klog.LevelInfo:
-		z.logger.Info("", fields...)
+		z.logger.Info(msg, fields...)
 	case klog.LevelWarn:
-		z.logger.Warn("", fields...)
+		z.logger.Warn(msg, fields...)
 	case klog.LevelError:
-		z.logger.Error("", fields...)
+		z.logger.Error(msg, fields...)
 	case klog.LevelFatal:
-		z.logger.Fatal("", fields...)
+		z.logger.Fatal(msg, fields...)
 	}
 
 	return nil
@@ -0,0 +1,55 @@
+// Package log 是本模块统一的日志门面
+//
+// 模块内的各个组件（mqtt、http client、fileutil 等）都通过 WithLogger 选项注入 Logger，
+// 未注入时使用此处的全局 Logger。全局 Logger 是一个代理，SetLogger 之后已创建的组件也会随之切换。
+// zap、slog 与 kratos log 之间的互相转换见 log/adapter 包。
+package log
+
+import (
+	"sync"
+
+	klog "github.com/go-kratos/kratos/v2/log"
+)
+
+// Logger 统一的日志接口，与 kratos log.Logger 保持一致
+type Logger = klog.Logger
+
+var global = &appliance{}
+
+// appliance 全局 Logger 的代理，保证 SetLogger 对所有已持有它的组件生效
+type appliance struct {
+	mu     sync.RWMutex
+	logger Logger
+}
+
+// Log 实现 Logger 接口
+func (a *appliance) Log(level klog.Level, keyvals ...any) error {
+	a.mu.RLock()
+	l := a.logger
+	a.mu.RUnlock()
+	if l == nil {
+		// 未设置时回落到 kratos 的全局 Logger
+		l = klog.GetLogger()
+	}
+	return l.Log(level, keyvals...)
+}
+
+// SetLogger 设置全局 Logger，传入 nil 时恢复为 kratos 的全局 Logger
+func SetLogger(l Logger) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.logger = l
+}
+
+// GetLogger 返回全局 Logger 的代理
+func GetLogger() Logger {
+	return global
+}
+
+// NewHelper 使用指定 Logger 创建 Helper，l 为 nil 时使用全局 Logger
+func NewHelper(l Logger) *klog.Helper {
+	if l == nil {
+		l = global
+	}
+	return klog.NewHelper(l)
+}
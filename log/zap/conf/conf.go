@@ -19,4 +19,7 @@ type ZapConf struct {
 	Compress      bool   `yaml:"compress"`      //是否压缩
 	MaxBackups    int32  `yaml:"maxBackups"`    //最大备份数
 	TimeRotation  int32  `yaml:"timeRotation"`  //时间轮转类型: "minute", "hour" 或 "day"
+	Layout        string `yaml:"layout"`        //文件布局: level（按级别，默认）、single（所有级别一个文件）、module（按 logger 名称）、single_error（所有级别一个文件 + 单独的错误文件）
+	FileName      string `yaml:"fileName"`      //文件名模板，支持 {time} {date} {hour} {minute} {name}，默认 {time}-{name}.log
+	DirName       string `yaml:"dirName"`       //子目录模板，支持 {month} {date} {name}，默认 {month}/{name}
 }
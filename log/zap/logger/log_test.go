@@ -1,11 +1,25 @@
 package logger
 
 import (
+	"os"
 	"sync"
 	"testing"
+
+	"go.uber.org/zap/zapcore"
 )
 
+func TestNoopBeforeSetup(t *testing.T) {
+	Log.Info("should be discarded")
+	Slog.Infof("should be discarded %s", "too")
+	if _, err := os.Stat("logs"); !os.IsNotExist(err) {
+		t.Fatalf("logs directory should not be created before Setup, stat err: %v", err)
+	}
+}
+
 func TestLog(t *testing.T) {
+	logs := SetupObserver(zapcore.DebugLevel)
+	defer Replace(nil)
+
 	r := func() {
 		for range 100 {
 			Log.Debug("test debug")
@@ -28,4 +42,10 @@ func TestLog(t *testing.T) {
 	}
 	group.Wait()
 
+	if got := logs.Len(); got != 10*100*8 {
+		t.Errorf("expect %d entries, got %d", 10*100*8, got)
+	}
+	if got := logs.FilterMessage("test infof infof").Len(); got != 10*100 {
+		t.Errorf("expect %d infof entries, got %d", 10*100, got)
+	}
 }
@@ -1,14 +1,52 @@
+// Package logger 提供全局的 zap Logger
+//
+// 导入本包不会产生任何副作用：Setup 之前 Log 与 Slog 均为 no-op，
+// 需要由 main 函数显式调用 Setup 完成初始化；测试中可使用 SetupObserver 将日志收集到内存。
 package logger
 
 import (
 	z "github.com/jiushengTech/common/log/zap"
+	"github.com/jiushengTech/common/log/zap/conf"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
-var Log *zap.Logger
-var Slog *zap.SugaredLogger
-
-func init() {
-	Log = z.DefaultZapLogger()
+var (
+	// Log 全局 zap.Logger，Setup 之前为 no-op
+	Log = zap.NewNop()
+	// Slog 全局 zap.SugaredLogger，Setup 之前为 no-op
 	Slog = Log.Sugar()
+)
+
+// Setup 根据配置初始化全局 Logger，c 为 nil 时使用 z.DefaultZapConf()
+// 日志目录与日志文件在此时才会创建
+func Setup(c *conf.ZapConf) *zap.Logger {
+	if c == nil {
+		c = z.DefaultZapConf()
+	}
+	Replace(z.NewZapLogger(c))
+	return Log
+}
+
+// Replace 使用已有的 zap.Logger 替换全局 Logger，l 为 nil 时恢复为 no-op
+func Replace(l *zap.Logger) {
+	if l == nil {
+		l = zap.NewNop()
+	}
+	Log = l
+	Slog = l.Sugar()
+}
+
+// NewObserver 创建将日志收集到内存的 zap.Logger，用于测试断言
+func NewObserver(level zapcore.Level) (*zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(level)
+	return zap.New(core), logs
+}
+
+// SetupObserver 使用内存 Logger 替换全局 Logger，返回收集到的日志
+func SetupObserver(level zapcore.Level) *observer.ObservedLogs {
+	l, logs := NewObserver(level)
+	Replace(l)
+	return logs
 }
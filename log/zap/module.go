@@ -0,0 +1,119 @@
+package zap
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/jiushengTech/common/log/zap/conf"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ zapcore.Core = (*moduleCore)(nil)
+
+// moduleCore 按 logger 名称将日志写入不同的文件
+// logger.Named("mqtt") 写入 mqtt 文件，未命名的 logger 写入 FileNameDefaultModule 文件
+type moduleCore struct {
+	zapcore.LevelEnabler
+	enc     zapcore.Encoder
+	writers *moduleWriters
+}
+
+// moduleWriters 各模块的写入器，按需创建并在 With 派生的 Core 之间共享
+type moduleWriters struct {
+	mu      sync.RWMutex
+	conf    *conf.ZapConf
+	writers map[string]zapcore.WriteSyncer
+}
+
+func newModuleCore(c *conf.ZapConf, enc zapcore.Encoder, enab zapcore.LevelEnabler) zapcore.Core {
+	return &moduleCore{
+		LevelEnabler: enab,
+		enc:          enc,
+		writers: &moduleWriters{
+			conf:    c,
+			writers: make(map[string]zapcore.WriteSyncer),
+		},
+	}
+}
+
+// With 为 Core 附加字段
+func (m *moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := m.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &moduleCore{
+		LevelEnabler: m.LevelEnabler,
+		enc:          enc,
+		writers:      m.writers,
+	}
+}
+
+// Check 判断是否需要记录该条日志
+func (m *moduleCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if m.Enabled(entry.Level) {
+		return ce.AddCore(entry, m)
+	}
+	return ce
+}
+
+// Write 将日志写入 logger 名称对应的文件
+func (m *moduleCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := m.enc.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	ws := m.writers.get(entry.LoggerName)
+	if _, err = ws.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if entry.Level > zapcore.ErrorLevel {
+		// 与 ioCore 保持一致，panic 和 fatal 前刷新
+		_ = ws.Sync()
+	}
+	return nil
+}
+
+// Sync 刷新所有模块的写入器
+func (m *moduleCore) Sync() error {
+	m.writers.mu.RLock()
+	defer m.writers.mu.RUnlock()
+	var err error
+	for _, ws := range m.writers.writers {
+		if e := ws.Sync(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// get 获取模块对应的写入器，不存在时创建
+func (w *moduleWriters) get(loggerName string) zapcore.WriteSyncer {
+	name := moduleFileName(loggerName)
+
+	w.mu.RLock()
+	ws, ok := w.writers[name]
+	w.mu.RUnlock()
+	if ok {
+		return ws
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if ws, ok = w.writers[name]; ok {
+		return ws
+	}
+	ws = GetWriteSyncer(w.conf, name)
+	w.writers[name] = ws
+	return ws
+}
+
+// moduleFileName 将 logger 名称转换为可用作文件名的模块名
+func moduleFileName(loggerName string) string {
+	if loggerName == "" {
+		return FileNameDefaultModule
+	}
+	return strings.NewReplacer("/", "_", "\\", "_", " ", "_").Replace(loggerName)
+}
@@ -3,6 +3,7 @@ package zap
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -58,7 +59,7 @@ func (t *TimeRotationHook) Write(p []byte) (n int, err error) {
 			// 关闭旧文件（重要）
 			_ = t.Lumberjack.Close()
 			// 生成新文件名和轮转时间
-			logFileName, nextRotation := generateFileNameAndRotation(now, int(t.Config.TimeRotation), t.Level)
+			logFileName, nextRotation := generateFileNameAndRotation(now, t.Config, t.Level)
 			logFilePath := filepath.Join(t.LevelDir, logFileName)
 			// 创建新的 lumberjack 实例
 			t.Lumberjack = &lumberjack.Logger{
@@ -97,11 +98,12 @@ func (t *TimeRotationHook) Sync() error {
 }
 
 // NewTimeRotationWriter 创建一个支持时间轮转的日志写入器
+// level 为文件名模板中的 {name}，可以是日志级别、模块名或 all、error
 func NewTimeRotationWriter(c *conf.ZapConf, level, levelDir string) *TimeRotationHook {
 	now := time.Now()
 
 	// 生成文件名和计算下一次轮转时间
-	logFileName, nextRotation := generateFileNameAndRotation(now, int(c.TimeRotation), level)
+	logFileName, nextRotation := generateFileNameAndRotation(now, c, level)
 
 	logFilePath := filepath.Join(levelDir, logFileName)
 
@@ -153,27 +155,47 @@ func cleanupOldTrackers(now time.Time) {
 }
 
 // generateFileNameAndRotation 根据轮转类型生成文件名和下一次轮转时间
-func generateFileNameAndRotation(now time.Time, rotationType int, level string) (string, time.Time) {
-	var logFileName string
+func generateFileNameAndRotation(now time.Time, c *conf.ZapConf, name string) (string, time.Time) {
+	var timePart string
 	var nextRotation time.Time
-	switch rotationType {
+	switch c.TimeRotation {
 	case RotateMinutely:
-		// 分钟级别轮转 - 文件名格式: 2006-01-02-15-04-level.log
-		logFileName = fmt.Sprintf("%s-%02d-%02d-%s.log", now.Format(time.DateOnly), now.Hour(), now.Minute(), level)
+		// 分钟级别轮转 - 时间格式: 2006-01-02-15-04
+		timePart = fmt.Sprintf("%s-%02d-%02d", now.Format(time.DateOnly), now.Hour(), now.Minute())
 		nextRotation = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute()+1, 0, 0, now.Location())
 	case RotateHourly:
-		// 小时级别轮转 - 文件名格式: 2006-01-02-15-level.log
-		logFileName = fmt.Sprintf("%s-%02d-%s.log", now.Format(time.DateOnly), now.Hour(), level)
+		// 小时级别轮转 - 时间格式: 2006-01-02-15
+		timePart = fmt.Sprintf("%s-%02d", now.Format(time.DateOnly), now.Hour())
 		nextRotation = time.Date(now.Year(), now.Month(), now.Day(), now.Hour()+1, 0, 0, 0, now.Location())
-	case RotateDaily:
-		// 天级别轮转 - 文件名格式: 2006-01-02-level.log
-		logFileName = fmt.Sprintf("%s-%s.log", now.Format(time.DateOnly), level)
-		nextRotation = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
 	default:
-		// 默认按照天切割
-		logFileName = fmt.Sprintf("%s-%s.log", now.Format(time.DateOnly), level)
+		// 天级别轮转（默认） - 时间格式: 2006-01-02
+		timePart = now.Format(time.DateOnly)
 		nextRotation = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
 	}
 
+	tpl := c.FileName
+	if tpl == "" {
+		tpl = DefaultFileName
+	}
+	logFileName := strings.NewReplacer(
+		"{time}", timePart,
+		"{date}", now.Format(time.DateOnly),
+		"{hour}", fmt.Sprintf("%02d", now.Hour()),
+		"{minute}", fmt.Sprintf("%02d", now.Minute()),
+		"{name}", name,
+	).Replace(tpl)
 	return logFileName, nextRotation
 }
+
+// generateDirName 根据子目录模板生成日志子目录
+func generateDirName(now time.Time, c *conf.ZapConf, name string) string {
+	tpl := c.DirName
+	if tpl == "" {
+		tpl = DefaultDirName
+	}
+	return filepath.FromSlash(strings.NewReplacer(
+		"{month}", now.Format("2006-01"),
+		"{date}", now.Format(time.DateOnly),
+		"{name}", name,
+	).Replace(tpl))
+}
@@ -22,6 +22,30 @@ const (
 	DefaultMaxAge      = 30 // 天
 	DefaultEncodeLevel = "LowercaseColorLevelEncoder"
 	DefaultStackKey    = "stack"
+	DefaultFileName    = "{time}-{name}.log"
+	DefaultDirName     = "{month}/{name}"
+)
+
+// 日志文件布局
+const (
+	// LayoutLevel 每个级别一个文件（默认）
+	LayoutLevel = "level"
+	// LayoutSingle 所有级别写入同一个文件
+	LayoutSingle = "single"
+	// LayoutModule 按 logger 名称分文件，logger.Named("mqtt") 写入 mqtt 文件
+	LayoutModule = "module"
+	// LayoutSingleError 所有级别写入同一个文件，同时将 error 及以上级别单独写入 error 文件
+	LayoutSingleError = "single_error"
+)
+
+// 非按级别布局时使用的文件名称
+const (
+	// FileNameAll 包含所有级别的文件名称
+	FileNameAll = "all"
+	// FileNameError 只包含错误日志的文件名称
+	FileNameError = "error"
+	// FileNameDefaultModule 未命名 logger 在按模块布局下使用的文件名称
+	FileNameDefaultModule = "app"
 )
 
 // NewZapLogger 创建并返回一个 zapLogger 实例
@@ -62,8 +86,14 @@ func NewZapLogger(c *conf.ZapConf) *zap.Logger {
 	return zap.New(zapcore.NewTee(cores...), options...)
 }
 
+// DefaultZapLogger 使用 DefaultZapConf 创建 zapLogger
 func DefaultZapLogger() *zap.Logger {
-	return NewZapLogger(&conf.ZapConf{
+	return NewZapLogger(DefaultZapConf())
+}
+
+// DefaultZapConf 返回默认的 zap 配置：开发模式、debug 级别、按小时轮转并同时输出到控制台
+func DefaultZapConf() *conf.ZapConf {
+	return &conf.ZapConf{
 		Model:         "dev",                        // 开发模式配置
 		Level:         "debug",                      // 日志级别设置为 debug（捕获 debug、info、warn、error 等）
 		Format:        "console",                    // 日志输出格式（console 或 JSON）
@@ -78,7 +108,7 @@ func DefaultZapLogger() *zap.Logger {
 		Compress:      false,                        // 是否压缩/归档旧日志文件
 		MaxBackups:    10,                           // 保留的旧日志文件的最大数量
 		TimeRotation:  RotateHourly,                 // 时间轮转类型: "0:minute", "1:hour" 或 "2:day"
-	})
+	}
 }
 
 // GetEncoder 获取编码器
@@ -145,6 +175,39 @@ func CustomTimeEncoder(t time.Time, encoder zapcore.PrimitiveArrayEncoder) {
 func getZapCores(c *conf.ZapConf) []zapcore.Core {
 	cores := make([]zapcore.Core, 0, 2)
 	minLevel := TransportLevel(c.Level)
+	levelFunc := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		return level >= minLevel
+	})
+
+	switch c.Layout {
+	case LayoutSingle:
+		cores = append(cores, zapcore.NewCore(GetEncoder(c, false), GetWriteSyncer(c, FileNameAll), levelFunc))
+	case LayoutSingleError:
+		errorFunc := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+			return level >= minLevel && level >= zapcore.ErrorLevel
+		})
+		cores = append(cores,
+			zapcore.NewCore(GetEncoder(c, false), GetWriteSyncer(c, FileNameAll), levelFunc),
+			zapcore.NewCore(GetEncoder(c, false), GetWriteSyncer(c, FileNameError), errorFunc),
+		)
+	case LayoutModule:
+		cores = append(cores, newModuleCore(c, GetEncoder(c, false), levelFunc))
+	default:
+		cores = append(cores, getLevelCores(c, minLevel)...)
+	}
+
+	// 如果需要控制台输出，添加控制台Core
+	if c.LogInConsole {
+		consoleCore := createConsoleCore(c, levelFunc)
+		cores = append(cores, consoleCore)
+	}
+
+	return cores
+}
+
+// getLevelCores 为每个级别创建单独的文件Core
+func getLevelCores(c *conf.ZapConf, minLevel zapcore.Level) []zapcore.Core {
+	cores := make([]zapcore.Core, 0, 7)
 
 	// 为每个级别创建单独的Core，提高性能
 	levels := []zapcore.Level{
@@ -168,16 +231,6 @@ func getZapCores(c *conf.ZapConf) []zapcore.Core {
 			cores = append(cores, fileCore)
 		}
 	}
-
-	// 如果需要控制台输出，添加控制台Core
-	if c.LogInConsole {
-		levelFunc := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
-			return level >= minLevel
-		})
-		consoleCore := createConsoleCore(c, levelFunc)
-		cores = append(cores, consoleCore)
-	}
-
 	return cores
 }
 
@@ -195,20 +248,15 @@ func createConsoleCore(c *conf.ZapConf, levelFunc zap.LevelEnablerFunc) zapcore.
 }
 
 // GetWriteSyncer 创建文件日志写入器，支持按照大小和时间切割
-func GetWriteSyncer(c *conf.ZapConf, level string) zapcore.WriteSyncer {
+// name 为文件名称（级别、模块名或 all、error），目录和文件名由 DirName、FileName 模板决定
+func GetWriteSyncer(c *conf.ZapConf, name string) zapcore.WriteSyncer {
 	// 创建日志目录
-	logPath := filepath.Join(c.Director, time.Now().Format("2006-01"))
-	if err := os.MkdirAll(logPath, os.ModePerm); err != nil {
+	logDir := filepath.Join(c.Director, generateDirName(time.Now(), c, name))
+	if err := os.MkdirAll(logDir, os.ModePerm); err != nil {
 		panic(fmt.Sprintf("创建日志目录失败: %v", err))
 	}
 
-	// 创建级别目录
-	levelDir := filepath.Join(logPath, level)
-	if err := os.MkdirAll(levelDir, os.ModePerm); err != nil {
-		panic(fmt.Sprintf("创建日志级别目录失败: %v", err))
-	}
-
-	writer := NewTimeRotationWriter(c, level, levelDir)
+	writer := NewTimeRotationWriter(c, name, logDir)
 	return zapcore.AddSync(writer)
 }
 
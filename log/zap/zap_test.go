@@ -0,0 +1,186 @@
+package zap
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jiushengTech/common/log/zap/conf"
+	uzap "go.uber.org/zap"
+)
+
+// logFiles 返回目录下所有日志文件相对于 dir 的路径
+func logFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestLayouts(t *testing.T) {
+	month := time.Now().Format("2006-01")
+	date := time.Now().Format(time.DateOnly)
+
+	tests := []struct {
+		name   string
+		layout string
+		want   []string
+	}{
+		{
+			name:   "level",
+			layout: LayoutLevel,
+			want: []string{
+				month + "/error/" + date + "-error.log",
+				month + "/info/" + date + "-info.log",
+			},
+		},
+		{
+			name:   "single",
+			layout: LayoutSingle,
+			want:   []string{month + "/all/" + date + "-all.log"},
+		},
+		{
+			name:   "singleError",
+			layout: LayoutSingleError,
+			want: []string{
+				month + "/all/" + date + "-all.log",
+				month + "/error/" + date + "-error.log",
+			},
+		},
+		{
+			name:   "module",
+			layout: LayoutModule,
+			want: []string{
+				month + "/app/" + date + "-app.log",
+				month + "/mqtt/" + date + "-mqtt.log",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := NewZapLogger(&conf.ZapConf{
+				Level:        "info",
+				Format:       "json",
+				Director:     dir,
+				TimeRotation: RotateDaily,
+				Layout:       tt.layout,
+			})
+			l.Info("app info")
+			l.Named("mqtt").Error("mqtt error")
+			_ = l.Sync()
+
+			got := logFiles(t, dir)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("expect files %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLayoutSingleKeepsOrder(t *testing.T) {
+	dir := t.TempDir()
+	l := NewZapLogger(&conf.ZapConf{
+		Level:        "debug",
+		Format:       "json",
+		Director:     dir,
+		TimeRotation: RotateDaily,
+		Layout:       LayoutSingle,
+		FileName:     "app-{name}.log",
+		DirName:      "{name}",
+	})
+	l.Info("first")
+	l.Error("second")
+	l.Debug("third")
+	_ = l.Sync()
+
+	content := readFile(t, filepath.Join(dir, "all", "app-all.log"))
+	first, second, third := strings.Index(content, "first"), strings.Index(content, "second"), strings.Index(content, "third")
+	if first < 0 || second < first || third < second {
+		t.Errorf("expect entries in chronological order, got %q", content)
+	}
+}
+
+func TestLayoutModuleWith(t *testing.T) {
+	dir := t.TempDir()
+	l := NewZapLogger(&conf.ZapConf{
+		Format:       "json",
+		Director:     dir,
+		TimeRotation: RotateDaily,
+		Layout:       LayoutModule,
+		FileName:     "{name}.log",
+		DirName:      "modules",
+	})
+	l.Named("mqtt").With(uzap.String("topic", "a/b")).Info("subscribed")
+	_ = l.Sync()
+
+	content := readFile(t, filepath.Join(dir, "modules", "mqtt.log"))
+	if !strings.Contains(content, `"topic":"a/b"`) || !strings.Contains(content, "subscribed") {
+		t.Errorf("unexpected mqtt log content %q", content)
+	}
+}
+
+func TestGenerateFileNameAndRotation(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 5, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		conf     *conf.ZapConf
+		wantName string
+		wantNext time.Time
+	}{
+		{
+			name:     "minute",
+			conf:     &conf.ZapConf{TimeRotation: RotateMinutely},
+			wantName: "2025-06-01-10-05-info.log",
+			wantNext: time.Date(2025, 6, 1, 10, 6, 0, 0, time.Local),
+		},
+		{
+			name:     "hour",
+			conf:     &conf.ZapConf{TimeRotation: RotateHourly},
+			wantName: "2025-06-01-10-info.log",
+			wantNext: time.Date(2025, 6, 1, 11, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "template",
+			conf:     &conf.ZapConf{TimeRotation: RotateDaily, FileName: "svc-{name}-{date}-{hour}.log"},
+			wantName: "svc-info-2025-06-01-10.log",
+			wantNext: time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, next := generateFileNameAndRotation(now, tt.conf, "info")
+			if name != tt.wantName {
+				t.Errorf("expect name %q, got %q", tt.wantName, name)
+			}
+			if !next.Equal(tt.wantNext) {
+				t.Errorf("expect next rotation %v, got %v", tt.wantNext, next)
+			}
+		})
+	}
+}
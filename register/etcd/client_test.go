@@ -0,0 +1,245 @@
+package etcd
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var (
+	_ clientv3.KV      = (*fakeEtcd)(nil)
+	_ clientv3.Lease   = (*fakeEtcd)(nil)
+	_ clientv3.Watcher = (*fakeEtcd)(nil)
+)
+
+type fakeLease struct {
+	ttl  int64
+	keys map[string]struct{}
+	// keepalive channels, closed when the lease is lost
+	chans []chan *clientv3.LeaseKeepAliveResponse
+}
+
+type fakeWatch struct {
+	prefix string
+	ch     chan clientv3.WatchResponse
+}
+
+// fakeEtcd is an in-memory etcd serving as KV, Lease and Watcher, methods not overridden panic.
+// Get and Watch always match the key as prefix.
+type fakeEtcd struct {
+	clientv3.KV
+	clientv3.Lease
+	clientv3.Watcher
+
+	mu      sync.Mutex
+	seq     int64
+	data    map[string]*mvccpb.KeyValue
+	leases  map[clientv3.LeaseID]*fakeLease
+	watches []*fakeWatch
+	// canceled makes the watches closed at once, like etcd without leader
+	canceled   bool
+	watchCount int
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{
+		data:   make(map[string]*mvccpb.KeyValue),
+		leases: make(map[clientv3.LeaseID]*fakeLease),
+	}
+}
+
+// Close resolves the ambiguity between Lease.Close and Watcher.Close.
+func (f *fakeEtcd) Close() error { return nil }
+
+func (f *fakeEtcd) client() *clientv3.Client {
+	return &clientv3.Client{KV: f, Lease: f, Watcher: f}
+}
+
+// leaseOf reads the lease of put options, which is not exported by clientv3.Op.
+func leaseOf(key, val string, opts ...clientv3.OpOption) clientv3.LeaseID {
+	op := clientv3.OpPut(key, val, opts...)
+	return clientv3.LeaseID(reflect.ValueOf(op).FieldByName("leaseID").Int())
+}
+
+func (f *fakeEtcd) Put(_ context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := leaseOf(key, val, opts...)
+	if id != clientv3.NoLease {
+		l, ok := f.leases[id]
+		if !ok {
+			return nil, rpctypes.ErrLeaseNotFound
+		}
+		l.keys[key] = struct{}{}
+	}
+	f.seq++
+	kv := &mvccpb.KeyValue{Key: []byte(key), Value: []byte(val), Lease: int64(id), ModRevision: f.seq}
+	f.data[key] = kv
+	f.notify(&clientv3.Event{Type: mvccpb.PUT, Kv: kv})
+	return &clientv3.PutResponse{}, nil
+}
+
+func (f *fakeEtcd) Get(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &clientv3.GetResponse{}
+	for k, kv := range f.data {
+		if strings.HasPrefix(k, key) {
+			resp.Kvs = append(resp.Kvs, kv)
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeEtcd) Delete(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delete(key)
+	return &clientv3.DeleteResponse{}, nil
+}
+
+func (f *fakeEtcd) delete(key string) {
+	kv, ok := f.data[key]
+	if !ok {
+		return
+	}
+	delete(f.data, key)
+	f.notify(&clientv3.Event{Type: mvccpb.DELETE, Kv: kv})
+}
+
+func (f *fakeEtcd) notify(ev *clientv3.Event) {
+	for _, w := range f.watches {
+		if strings.HasPrefix(string(ev.Kv.Key), w.prefix) {
+			w.ch <- clientv3.WatchResponse{Events: []*clientv3.Event{ev}}
+		}
+	}
+}
+
+func (f *fakeEtcd) Grant(_ context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	id := clientv3.LeaseID(f.seq)
+	f.leases[id] = &fakeLease{ttl: ttl, keys: make(map[string]struct{})}
+	return &clientv3.LeaseGrantResponse{ID: id, TTL: ttl}, nil
+}
+
+func (f *fakeEtcd) Revoke(_ context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.expire(id) {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+	return &clientv3.LeaseRevokeResponse{}, nil
+}
+
+func (f *fakeEtcd) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	l, ok := f.leases[id]
+	if !ok {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+	ch := make(chan *clientv3.LeaseKeepAliveResponse)
+	l.chans = append(l.chans, ch)
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if l, ok := f.leases[id]; ok {
+			for i, c := range l.chans {
+				if c == ch {
+					l.chans = append(l.chans[:i], l.chans[i+1:]...)
+					close(ch)
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// expire drops the lease with its keys and closes its keepalive channels.
+func (f *fakeEtcd) expire(id clientv3.LeaseID) bool {
+	l, ok := f.leases[id]
+	if !ok {
+		return false
+	}
+	delete(f.leases, id)
+	for k := range l.keys {
+		f.delete(k)
+	}
+	for _, ch := range l.chans {
+		close(ch)
+	}
+	return true
+}
+
+// expireAll simulates losing all leases, e.g. after a network partition longer than the ttl.
+func (f *fakeEtcd) expireAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id := range f.leases {
+		f.expire(id)
+	}
+}
+
+func (f *fakeEtcd) leaseCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.leases)
+}
+
+func (f *fakeEtcd) Watch(ctx context.Context, key string, _ ...clientv3.OpOption) clientv3.WatchChan {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWatch{prefix: key, ch: make(chan clientv3.WatchResponse, 16)}
+	f.watchCount++
+	if f.canceled {
+		close(w.ch)
+		return w.ch
+	}
+	f.watches = append(f.watches, w)
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for i, fw := range f.watches {
+			if fw == w {
+				f.watches = append(f.watches[:i], f.watches[i+1:]...)
+				close(w.ch)
+				return
+			}
+		}
+	}()
+	return w.ch
+}
+
+// setCanceled sets whether the new watches are closed at once.
+func (f *fakeEtcd) setCanceled(canceled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.canceled = canceled
+}
+
+// watchCalls returns the number of Watch calls, and whether a watch is open.
+func (f *fakeEtcd) watchCalls() (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.watchCount, len(f.watches) > 0
+}
+
+func (f *fakeEtcd) leaseTTLs() []int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ttls := make([]int64, 0, len(f.leases))
+	for _, l := range f.leases {
+		ttls = append(ttls, l.ttl)
+	}
+	return ttls
+}
@@ -0,0 +1,225 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	klog "github.com/go-kratos/kratos/v2/log"
+	"github.com/jiushengTech/common/log"
+	"github.com/jiushengTech/kratos/v2/registry"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var (
+	ErrServiceInstanceNameEmpty = errors.New("kratos/etcd: ServiceInstance.Name can not be empty")
+	ErrServiceInstanceIDEmpty   = errors.New("kratos/etcd: ServiceInstance.ID can not be empty")
+)
+
+var (
+	_ registry.Registrar = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+// minRetryInterval is the first interval to register again after the lease is lost,
+// doubled on every failure up to the ttl.
+const minRetryInterval = 100 * time.Millisecond
+
+type options struct {
+	namespace string
+	ttl       time.Duration
+	logger    log.Logger
+}
+
+// Option is etcd registry option.
+type Option func(o *options)
+
+// WithNamespace with namespace option, the instance key is namespace/name/id.
+func WithNamespace(ns string) Option {
+	return func(o *options) { o.namespace = ns }
+}
+
+// WithRegisterTTL with register ttl option, the ttl of the lease the instance key is attached to.
+// The lease ttl is in seconds, the ttl is rounded up to seconds, at least 1s.
+func WithRegisterTTL(ttl time.Duration) Option {
+	return func(o *options) { o.ttl = ttl }
+}
+
+// WithLogger with logger option.
+func WithLogger(logger log.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// Registry is etcd registry.
+type Registry struct {
+	opts   options
+	client *clientv3.Client
+	kv     clientv3.KV
+	lease  clientv3.Lease
+	log    *klog.Helper
+
+	mu sync.Mutex
+	// cancels stop the keepalive of the registered instances, keyed by instance key
+	cancels map[string]context.CancelFunc
+}
+
+// New new an etcd registry.
+func New(client *clientv3.Client, opts ...Option) *Registry {
+	op := options{
+		namespace: "/microservices",
+		ttl:       15 * time.Second,
+		logger:    log.GetLogger(),
+	}
+	for _, option := range opts {
+		option(&op)
+	}
+	// etcd grants the leases in seconds, Grant(0) would use the minimum ttl of the server
+	op.ttl = max((op.ttl + time.Second - 1).Truncate(time.Second), time.Second)
+	return &Registry{
+		opts:    op,
+		client:  client,
+		kv:      client.KV,
+		lease:   client.Lease,
+		log:     log.NewHelper(op.logger),
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// Register the registration.
+// The instance key is attached to a lease kept alive in background, and registered again
+// with a new lease once the lease is lost, e.g. after a long network partition.
+func (r *Registry) Register(ctx context.Context, si *registry.ServiceInstance) error {
+	if si.Name == "" {
+		return ErrServiceInstanceNameEmpty
+	}
+	if si.ID == "" {
+		return ErrServiceInstanceIDEmpty
+	}
+	key := r.instanceKey(si.Name, si.ID)
+	value, err := json.Marshal(si)
+	if err != nil {
+		return err
+	}
+	leaseID, err := r.register(ctx, key, string(value))
+	if err != nil {
+		return err
+	}
+
+	hctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	if c, ok := r.cancels[key]; ok {
+		c()
+	}
+	r.cancels[key] = cancel
+	r.mu.Unlock()
+	go r.heartBeat(hctx, leaseID, key, string(value))
+	return nil
+}
+
+// Deregister the registration.
+func (r *Registry) Deregister(ctx context.Context, si *registry.ServiceInstance) error {
+	key := r.instanceKey(si.Name, si.ID)
+	r.mu.Lock()
+	if cancel, ok := r.cancels[key]; ok {
+		cancel()
+		delete(r.cancels, key)
+	}
+	r.mu.Unlock()
+	_, err := r.kv.Delete(ctx, key)
+	return err
+}
+
+// GetService return the service instances in etcd according to the service name.
+func (r *Registry) GetService(ctx context.Context, name string) ([]*registry.ServiceInstance, error) {
+	resp, err := r.kv.Get(ctx, r.serviceKey(name), clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*registry.ServiceInstance, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		si, err := unmarshal(kv.Value)
+		if err != nil {
+			r.log.Warnf("[etcd] skip invalid instance %s: %v", kv.Key, err)
+			continue
+		}
+		items = append(items, si)
+	}
+	return items, nil
+}
+
+// Watch creates a watcher according to the service name.
+func (r *Registry) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	return newWatcher(ctx, r, name), nil
+}
+
+// register grants a lease and puts the instance with it.
+func (r *Registry) register(ctx context.Context, key, value string) (clientv3.LeaseID, error) {
+	grant, err := r.lease.Grant(ctx, int64(r.opts.ttl.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	if _, err = r.kv.Put(ctx, key, value, clientv3.WithLease(grant.ID)); err != nil {
+		return 0, err
+	}
+	return grant.ID, nil
+}
+
+// heartBeat keeps the lease alive until ctx is done, and registers the instance again
+// when the keepalive stops. The lease is revoked when ctx is done.
+func (r *Registry) heartBeat(ctx context.Context, leaseID clientv3.LeaseID, key, value string) {
+	defer func() {
+		// revoke with a fresh context, ctx is already done
+		rctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, _ = r.lease.Revoke(rctx, leaseID)
+	}()
+	for {
+		ch, err := r.lease.KeepAlive(ctx, leaseID)
+		if err == nil {
+			// the channel is closed once the lease is lost or ctx is done
+			for range ch {
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		r.log.Warnf("[etcd] lease of %s is lost, registering again", key)
+
+		interval := minRetryInterval
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+			id, err := r.register(ctx, key, value)
+			if err == nil {
+				leaseID = id
+				break
+			}
+			r.log.Errorf("[etcd] register %s again: %v", key, err)
+			if interval *= 2; interval > r.opts.ttl {
+				interval = r.opts.ttl
+			}
+		}
+	}
+}
+
+func (r *Registry) serviceKey(name string) string {
+	return fmt.Sprintf("%s/%s/", r.opts.namespace, name)
+}
+
+func (r *Registry) instanceKey(name, id string) string {
+	return r.serviceKey(name) + id
+}
+
+func unmarshal(data []byte) (*registry.ServiceInstance, error) {
+	si := new(registry.ServiceInstance)
+	if err := json.Unmarshal(data, si); err != nil {
+		return nil, err
+	}
+	return si, nil
+}
@@ -0,0 +1,211 @@
+package etcd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+)
+
+// eventually polls cond until it is true or the timeout.
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not satisfied before timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	f := newFakeEtcd()
+	r := New(f.client(), WithNamespace("/test"))
+	ctx := context.Background()
+	s1 := &registry.ServiceInstance{
+		ID:        "1",
+		Name:      "helloworld",
+		Version:   "v1.0.0",
+		Metadata:  map[string]string{"weight": "50"},
+		Endpoints: []string{"grpc://127.0.0.1:9000"},
+	}
+	s2 := &registry.ServiceInstance{ID: "2", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.2:9000"}}
+	other := &registry.ServiceInstance{ID: "3", Name: "helloworld2", Endpoints: []string{"grpc://127.0.0.3:9000"}}
+	for _, si := range []*registry.ServiceInstance{s1, s2, other} {
+		if err := r.Register(ctx, si); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := f.data["/test/helloworld/1"]; !ok {
+		t.Fatalf("expect key /test/helloworld/1, got %v", f.data)
+	}
+
+	items, err := r.GetService(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expect 2 instances, got %d", len(items))
+	}
+	for _, item := range items {
+		if item.ID == "1" && (item.Version != "v1.0.0" || item.Metadata["weight"] != "50" || item.Endpoints[0] != "grpc://127.0.0.1:9000") {
+			t.Errorf("unexpected instance %+v", item)
+		}
+	}
+
+	if err = r.Deregister(ctx, s1); err != nil {
+		t.Fatal(err)
+	}
+	if items, err = r.GetService(ctx, "helloworld"); err != nil || len(items) != 1 || items[0].ID != "2" {
+		t.Fatalf("expect instance 2 after Deregister, got %+v, %v", items, err)
+	}
+	// the lease of the deregistered instance is revoked
+	eventually(t, func() bool { return f.leaseCount() == 2 })
+
+	if err = r.Register(ctx, &registry.ServiceInstance{Name: "helloworld"}); err != ErrServiceInstanceIDEmpty {
+		t.Errorf("expect ErrServiceInstanceIDEmpty, got %v", err)
+	}
+}
+
+func TestRegistry_LeaseLost(t *testing.T) {
+	f := newFakeEtcd()
+	r := New(f.client(), WithRegisterTTL(time.Second))
+	ctx := context.Background()
+	si := &registry.ServiceInstance{ID: "1", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	if err := r.Register(ctx, si); err != nil {
+		t.Fatal(err)
+	}
+	lease := f.data["/microservices/helloworld/1"].Lease
+
+	f.expireAll()
+	if items, _ := r.GetService(ctx, "helloworld"); len(items) != 0 {
+		t.Fatalf("expect no instance after the lease is lost, got %d", len(items))
+	}
+	eventually(t, func() bool {
+		items, _ := r.GetService(ctx, "helloworld")
+		return len(items) == 1
+	})
+	f.mu.Lock()
+	kv := f.data["/microservices/helloworld/1"]
+	f.mu.Unlock()
+	if kv.Lease == lease {
+		t.Errorf("expect registered again with a new lease, got %d", kv.Lease)
+	}
+
+	if err := r.Deregister(ctx, si); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool { return f.leaseCount() == 0 })
+}
+
+func TestWatcher(t *testing.T) {
+	f := newFakeEtcd()
+	r := New(f.client())
+	ctx := context.Background()
+	s1 := &registry.ServiceInstance{ID: "1", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	if err := r.Register(ctx, s1); err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Watch(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := w.Next()
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expect 1 instance on first Next, got %d, %v", len(items), err)
+	}
+
+	s2 := &registry.ServiceInstance{ID: "2", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.2:9000"}}
+	if err = r.Register(ctx, s2); err != nil {
+		t.Fatal(err)
+	}
+	if items, err = w.Next(); err != nil || len(items) != 2 {
+		t.Fatalf("expect 2 instances after Register, got %d, %v", len(items), err)
+	}
+
+	if err = r.Deregister(ctx, s1); err != nil {
+		t.Fatal(err)
+	}
+	if items, err = w.Next(); err != nil || len(items) != 1 || items[0].ID != "2" {
+		t.Fatalf("expect instance 2 after Deregister, got %+v, %v", items, err)
+	}
+
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Next(); err == nil {
+		t.Error("expect error after Stop")
+	}
+}
+
+func TestRegistry_TTL(t *testing.T) {
+	for _, tt := range []struct {
+		ttl  time.Duration
+		want int64
+	}{
+		{0, 1},
+		{500 * time.Millisecond, 1},
+		{1500 * time.Millisecond, 2},
+		{15 * time.Second, 15},
+	} {
+		f := newFakeEtcd()
+		r := New(f.client(), WithRegisterTTL(tt.ttl))
+		si := &registry.ServiceInstance{ID: "1", Name: "helloworld"}
+		if err := r.Register(context.Background(), si); err != nil {
+			t.Fatal(err)
+		}
+		if ttls := f.leaseTTLs(); len(ttls) != 1 || ttls[0] != tt.want {
+			t.Errorf("ttl %v: expect lease ttl %d, got %v", tt.ttl, tt.want, ttls)
+		}
+		_ = r.Deregister(context.Background(), si)
+	}
+}
+
+func TestWatcher_Canceled(t *testing.T) {
+	f := newFakeEtcd()
+	f.setCanceled(true)
+	r := New(f.client())
+	w, err := r.Watch(context.Background(), "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	if _, err = w.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		items []*registry.ServiceInstance
+		err   error
+	}
+	next := make(chan result, 1)
+	go func() {
+		items, err := w.Next()
+		next <- result{items, err}
+	}()
+	// the watch is retried with backoff, 100ms, 200ms, ...
+	time.Sleep(350 * time.Millisecond)
+	if n, _ := f.watchCalls(); n > 4 {
+		t.Fatalf("expect the watch retried with backoff, got %d watches", n)
+	}
+
+	f.setCanceled(false)
+	eventually(t, func() bool {
+		_, open := f.watchCalls()
+		return open
+	})
+	if err = r.Register(context.Background(), &registry.ServiceInstance{ID: "1", Name: "helloworld"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case res := <-next:
+		if res.err != nil || len(res.items) != 1 {
+			t.Errorf("expect 1 instance after watching again, got %+v, %v", res.items, res.err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Next not returned after watching again")
+	}
+}
@@ -0,0 +1,80 @@
+package etcd
+
+import (
+	"context"
+	"time"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var _ registry.Watcher = (*watcher)(nil)
+
+type watcher struct {
+	key         string
+	serviceName string
+	ctx         context.Context
+	cancel      context.CancelFunc
+	r           *Registry
+	watcher     clientv3.Watcher
+	watchChan   clientv3.WatchChan
+	first       bool
+	// retry is the interval to watch again after the watch is canceled, reset once a response is received
+	retry time.Duration
+}
+
+func newWatcher(ctx context.Context, r *Registry, name string) *watcher {
+	w := &watcher{
+		key:         r.serviceKey(name),
+		serviceName: name,
+		r:           r,
+		watcher:     r.client.Watcher,
+		first:       true,
+		retry:       minRetryInterval,
+	}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.watchChan = w.watch()
+	return w
+}
+
+func (w *watcher) watch() clientv3.WatchChan {
+	// require leader so the watch is not stuck on a member partitioned from the cluster
+	return w.watcher.Watch(clientv3.WithRequireLeader(w.ctx), w.key, clientv3.WithPrefix(), clientv3.WithRev(0))
+}
+
+// Next returns all instances of the service on the first call, then blocks until the instances change.
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	if w.first {
+		w.first = false
+		return w.r.GetService(w.ctx, w.serviceName)
+	}
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case resp, ok := <-w.watchChan:
+			if !ok || resp.Err() != nil {
+				// the watch is canceled by etcd, e.g. compacted or no leader, watch again
+				// wait before watching again, the new watch may be canceled at once, e.g. still no leader
+				select {
+				case <-w.ctx.Done():
+					return nil, w.ctx.Err()
+				case <-time.After(w.retry):
+				}
+				if w.retry *= 2; w.retry > w.r.opts.ttl {
+					w.retry = w.r.opts.ttl
+				}
+				w.watchChan = w.watch()
+				continue
+			}
+			w.retry = minRetryInterval
+			return w.r.GetService(w.ctx, w.serviceName)
+		}
+	}
+}
+
+// Stop close the watcher.
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
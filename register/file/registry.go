@@ -0,0 +1,180 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	klog "github.com/go-kratos/kratos/v2/log"
+	"github.com/jiushengTech/common/log"
+	"github.com/jiushengTech/common/register/memory"
+	"github.com/jiushengTech/kratos/v2/registry"
+	"gopkg.in/yaml.v3"
+)
+
+var _ registry.Discovery = (*Registry)(nil)
+
+// File is the content of the instances file, for example in YAML:
+//
+//	instances:
+//	  - id: "1"
+//	    name: helloworld
+//	    version: v1.0.0
+//	    metadata:
+//	      weight: "100"
+//	    endpoints:
+//	      - grpc://127.0.0.1:9000
+type File struct {
+	Instances []Instance `json:"instances" yaml:"instances"`
+}
+
+// Instance is a service instance in the file, the ID defaults to name-index when empty.
+type Instance struct {
+	ID        string            `json:"id" yaml:"id"`
+	Name      string            `json:"name" yaml:"name"`
+	Version   string            `json:"version" yaml:"version"`
+	Metadata  map[string]string `json:"metadata" yaml:"metadata"`
+	Endpoints []string          `json:"endpoints" yaml:"endpoints"`
+}
+
+type options struct {
+	logger log.Logger
+}
+
+// Option is file registry option.
+type Option func(o *options)
+
+// WithLogger with logger option.
+func WithLogger(logger log.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// Registry is a discovery reading instances from a YAML or JSON file, for local development and tests.
+// The file is watched and reloaded on changes, an invalid or empty file keeps the instances loaded before.
+type Registry struct {
+	path    string
+	mem     *memory.Registry
+	log     *klog.Helper
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// New new a file registry, the format is detected by the extension, .json for JSON and YAML otherwise.
+func New(path string, opts ...Option) (*Registry, error) {
+	op := options{logger: log.GetLogger()}
+	for _, option := range opts {
+		option(&op)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Registry{
+		path: path,
+		mem:  memory.New(),
+		log:  log.NewHelper(op.logger),
+		done: make(chan struct{}),
+	}
+	if err = r.load(); err != nil {
+		return nil, err
+	}
+	if r.watcher, err = fsnotify.NewWatcher(); err != nil {
+		return nil, err
+	}
+	// watch the directory, editors usually replace the file instead of writing it
+	if err = r.watcher.Add(filepath.Dir(path)); err != nil {
+		_ = r.watcher.Close()
+		return nil, err
+	}
+	go r.watch()
+	return r, nil
+}
+
+// GetService return the service instances in the file according to the service name.
+func (r *Registry) GetService(ctx context.Context, name string) ([]*registry.ServiceInstance, error) {
+	return r.mem.GetService(ctx, name)
+}
+
+// Watch creates a watcher according to the service name, notified when the instances in the file change.
+func (r *Registry) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	return r.mem.Watch(ctx, name)
+}
+
+// Close stops watching the file.
+func (r *Registry) Close() error {
+	err := r.watcher.Close()
+	<-r.done
+	return err
+}
+
+func (r *Registry) watch() {
+	defer close(r.done)
+	for {
+		select {
+		case ev, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if ev.Name != r.path || !ev.Has(fsnotify.Write|fsnotify.Create) {
+				continue
+			}
+			if err := r.load(); err != nil {
+				r.log.Errorf("[file] reload %s: %v", r.path, err)
+			}
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			r.log.Errorf("[file] watch %s: %v", r.path, err)
+		}
+	}
+}
+
+func (r *Registry) load() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		// the file is being written, or cleared by mistake
+		return fmt.Errorf("%s is empty", r.path)
+	}
+	f, err := parse(r.path, data)
+	if err != nil {
+		return err
+	}
+	instances := make([]*registry.ServiceInstance, 0, len(f.Instances))
+	for i, in := range f.Instances {
+		id := in.ID
+		if id == "" {
+			id = fmt.Sprintf("%s-%d", in.Name, i)
+		}
+		instances = append(instances, &registry.ServiceInstance{
+			ID:        id,
+			Name:      in.Name,
+			Version:   in.Version,
+			Metadata:  in.Metadata,
+			Endpoints: in.Endpoints,
+		})
+	}
+	return r.mem.Reset(instances)
+}
+
+func parse(path string, data []byte) (*File, error) {
+	f := new(File)
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, f)
+	} else {
+		err = yaml.Unmarshal(data, f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return f, nil
+}
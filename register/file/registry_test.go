@@ -0,0 +1,121 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testYAML = `
+instances:
+  - id: "1"
+    name: helloworld
+    version: v1.0.0
+    metadata:
+      weight: "50"
+    endpoints:
+      - grpc://127.0.0.1:9000
+  - name: helloworld
+    endpoints:
+      - grpc://127.0.0.2:9000
+  - name: other
+    endpoints:
+      - http://127.0.0.3:8000
+`
+
+// writeFile replaces the file like an editor does.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.yaml")
+	writeFile(t, path, testYAML)
+	r, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+
+	items, err := r.GetService(context.Background(), "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expect 2 instances, got %d", len(items))
+	}
+	if items[0].ID != "1" || items[0].Version != "v1.0.0" || items[0].Metadata["weight"] != "50" || items[0].Endpoints[0] != "grpc://127.0.0.1:9000" {
+		t.Errorf("unexpected instance %+v", items[0])
+	}
+	if items[1].ID != "helloworld-1" {
+		t.Errorf("expect default id helloworld-1, got %s", items[1].ID)
+	}
+}
+
+func TestRegistry_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	writeFile(t, path, `{"instances":[{"id":"1","name":"helloworld","endpoints":["grpc://127.0.0.1:9000"]}]}`)
+	r, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+	if items, _ := r.GetService(context.Background(), "helloworld"); len(items) != 1 || items[0].ID != "1" {
+		t.Fatalf("unexpected instances %+v", items)
+	}
+
+	if _, err = New(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expect error for missing file")
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.yaml")
+	writeFile(t, path, testYAML)
+	r, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+
+	w, err := r.Watch(context.Background(), "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Stop() }()
+	if items, err := w.Next(); err != nil || len(items) != 2 {
+		t.Fatalf("expect 2 instances on first Next, got %d, %v", len(items), err)
+	}
+
+	// an invalid file keeps the instances
+	writeFile(t, path, "instances: [")
+	writeFile(t, path, `
+instances:
+  - id: "3"
+    name: helloworld
+    endpoints:
+      - grpc://127.0.0.4:9000
+`)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		items, err := w.Next()
+		if err != nil || len(items) != 1 || items[0].ID != "3" {
+			t.Errorf("expect instance 3 after reload, got %+v, %v", items, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("watcher not notified after the file changed")
+	}
+}
@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+)
+
+var (
+	ErrServiceInstanceNameEmpty = errors.New("kratos/memory: ServiceInstance.Name can not be empty")
+	ErrServiceInstanceIDEmpty   = errors.New("kratos/memory: ServiceInstance.ID can not be empty")
+)
+
+var (
+	_ registry.Registrar = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+// Registry is an in-process registry, for local development and tests.
+type Registry struct {
+	mu sync.RWMutex
+	// services are the instances of every service in registration order
+	services map[string][]*registry.ServiceInstance
+	watchers map[string]map[*watcher]struct{}
+}
+
+// New new an in-process registry.
+func New() *Registry {
+	return &Registry{
+		services: make(map[string][]*registry.ServiceInstance),
+		watchers: make(map[string]map[*watcher]struct{}),
+	}
+}
+
+// Register the registration, an instance with the same ID is replaced.
+func (r *Registry) Register(_ context.Context, si *registry.ServiceInstance) error {
+	if si.Name == "" {
+		return ErrServiceInstanceNameEmpty
+	}
+	if si.ID == "" {
+		return ErrServiceInstanceIDEmpty
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := r.services[si.Name]
+	if i := index(list, si.ID); i >= 0 {
+		list[i] = clone(si)
+	} else {
+		r.services[si.Name] = append(list, clone(si))
+	}
+	r.notify(si.Name)
+	return nil
+}
+
+// Deregister the registration.
+func (r *Registry) Deregister(_ context.Context, si *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := r.services[si.Name]
+	i := index(list, si.ID)
+	if i < 0 {
+		return nil
+	}
+	if list = slices.Delete(list, i, i+1); len(list) == 0 {
+		delete(r.services, si.Name)
+	} else {
+		r.services[si.Name] = list
+	}
+	r.notify(si.Name)
+	return nil
+}
+
+// Reset replaces all instances of the registry at once, watchers of changed services are notified.
+func (r *Registry) Reset(instances []*registry.ServiceInstance) error {
+	services := make(map[string][]*registry.ServiceInstance)
+	for _, si := range instances {
+		if si.Name == "" {
+			return ErrServiceInstanceNameEmpty
+		}
+		if si.ID == "" {
+			return ErrServiceInstanceIDEmpty
+		}
+		list := services[si.Name]
+		if i := index(list, si.ID); i >= 0 {
+			list[i] = clone(si)
+		} else {
+			services[si.Name] = append(list, clone(si))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.services
+	r.services = services
+	for name := range r.watchers {
+		if !slices.EqualFunc(old[name], services[name], equal) {
+			r.notify(name)
+		}
+	}
+	return nil
+}
+
+// GetService return the service instances in memory according to the service name.
+func (r *Registry) GetService(_ context.Context, name string) ([]*registry.ServiceInstance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := r.services[name]
+	items := make([]*registry.ServiceInstance, 0, len(list))
+	for _, si := range list {
+		items = append(items, clone(si))
+	}
+	return items, nil
+}
+
+// Watch creates a watcher according to the service name.
+func (r *Registry) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	w := &watcher{
+		r:         r,
+		name:      name,
+		watchChan: make(chan struct{}, 1),
+	}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	// the first Next returns the current instances
+	w.watchChan <- struct{}{}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ws, ok := r.watchers[name]
+	if !ok {
+		ws = make(map[*watcher]struct{})
+		r.watchers[name] = ws
+	}
+	ws[w] = struct{}{}
+	return w, nil
+}
+
+// notify notifies the watchers of the service, r.mu must be held.
+func (r *Registry) notify(name string) {
+	for w := range r.watchers[name] {
+		select {
+		case w.watchChan <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (r *Registry) removeWatcher(w *watcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ws, ok := r.watchers[w.name]; ok {
+		delete(ws, w)
+		if len(ws) == 0 {
+			delete(r.watchers, w.name)
+		}
+	}
+}
+
+func index(list []*registry.ServiceInstance, id string) int {
+	return slices.IndexFunc(list, func(si *registry.ServiceInstance) bool { return si.ID == id })
+}
+
+// clone copies the instance, so callers can not modify the instances in memory.
+func clone(si *registry.ServiceInstance) *registry.ServiceInstance {
+	return &registry.ServiceInstance{
+		ID:        si.ID,
+		Name:      si.Name,
+		Version:   si.Version,
+		Metadata:  maps.Clone(si.Metadata),
+		Endpoints: slices.Clone(si.Endpoints),
+	}
+}
+
+func equal(a, b *registry.ServiceInstance) bool {
+	return a.ID == b.ID && a.Name == b.Name && a.Version == b.Version &&
+		maps.Equal(a.Metadata, b.Metadata) && slices.Equal(a.Endpoints, b.Endpoints)
+}
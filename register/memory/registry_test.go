@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+)
+
+func TestRegistry(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	s1 := &registry.ServiceInstance{ID: "1", Name: "helloworld", Metadata: map[string]string{"weight": "50"}, Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	s2 := &registry.ServiceInstance{ID: "2", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.2:9000"}}
+	for _, si := range []*registry.ServiceInstance{s1, s2} {
+		if err := r.Register(ctx, si); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items, err := r.GetService(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].ID != "1" || items[1].ID != "2" {
+		t.Fatalf("unexpected instances %+v", items)
+	}
+	// the instances returned are copies
+	items[0].Metadata["weight"] = "0"
+	if items, _ = r.GetService(ctx, "helloworld"); items[0].Metadata["weight"] != "50" {
+		t.Errorf("expect the registered instance unchanged, got %v", items[0].Metadata)
+	}
+
+	if err = r.Deregister(ctx, s1); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ = r.GetService(ctx, "helloworld"); len(items) != 1 || items[0].ID != "2" {
+		t.Fatalf("expect instance 2 after Deregister, got %+v", items)
+	}
+	if err = r.Register(ctx, &registry.ServiceInstance{Name: "helloworld"}); err != ErrServiceInstanceIDEmpty {
+		t.Errorf("expect ErrServiceInstanceIDEmpty, got %v", err)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	s1 := &registry.ServiceInstance{ID: "1", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	if err := r.Register(ctx, s1); err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Watch(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := w.Next()
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expect 1 instance on first Next, got %d, %v", len(items), err)
+	}
+
+	done := make(chan []*registry.ServiceInstance)
+	go func() {
+		items, _ := w.Next()
+		done <- items
+	}()
+	s2 := &registry.ServiceInstance{ID: "2", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.2:9000"}}
+	if err = r.Register(ctx, s2); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case items = <-done:
+		if len(items) != 2 {
+			t.Fatalf("expect 2 instances after Register, got %d", len(items))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watcher not notified after Register")
+	}
+
+	// Reset notifies only when the instances change
+	if err = r.Reset([]*registry.ServiceInstance{s1, s2}); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Reset([]*registry.ServiceInstance{s2}); err != nil {
+		t.Fatal(err)
+	}
+	if items, err = w.Next(); err != nil || len(items) != 1 || items[0].ID != "2" {
+		t.Fatalf("expect instance 2 after Reset, got %+v, %v", items, err)
+	}
+
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Next(); err == nil {
+		t.Error("expect error after Stop")
+	}
+	if len(r.watchers) != 0 {
+		t.Errorf("expect the watcher removed after Stop, got %d", len(r.watchers))
+	}
+}
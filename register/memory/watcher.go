@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+)
+
+var _ registry.Watcher = (*watcher)(nil)
+
+type watcher struct {
+	r         *Registry
+	name      string
+	ctx       context.Context
+	cancel    context.CancelFunc
+	watchChan chan struct{}
+}
+
+// Next returns the instances of the service on the first call, then blocks until the instances change.
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	select {
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	case <-w.watchChan:
+		return w.r.GetService(w.ctx, w.name)
+	}
+}
+
+// Stop close the watcher.
+func (w *watcher) Stop() error {
+	w.cancel()
+	w.r.removeWatcher(w)
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package register defines the metadata keys shared by the registry implementations.
+//
+// Every registry exposes the weight, cluster, health and zone of an instance in
+// ServiceInstance.Metadata under these keys, so selectors work the same on any registry.
+package register
+
+const (
+	// MetadataKind is the endpoint scheme of the instance, e.g. grpc or http.
+	MetadataKind = "kind"
+	// MetadataVersion is the version of the instance.
+	MetadataVersion = "version"
+	// MetadataWeight is the weight of the instance, the same key kratos selector reads.
+	MetadataWeight = "weight"
+	// MetadataCluster is the cluster the instance belongs to.
+	MetadataCluster = "cluster"
+	// MetadataHealthy is "true" or "false" according to the health reported by the registry.
+	MetadataHealthy = "healthy"
+	// MetadataZone is the zone (available zone) the instance is deployed in.
+	MetadataZone = "zone"
+	// MetadataTags is a comma separated tag list of the instance.
+	MetadataTags = "tags"
+)
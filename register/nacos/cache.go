@@ -0,0 +1,54 @@
+package nacos
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+)
+
+// cacheFile returns the cache file of a service, e.g. DEFAULT_GROUP@@helloworld.grpc.json.
+func cacheFile(dir, group, service string) string {
+	name := group + constant.SERVICE_INFO_SPLITER + service + ".json"
+	return filepath.Join(dir, strings.NewReplacer("/", "_", "\\", "_").Replace(name))
+}
+
+// saveCache writes the instances to the cache file atomically.
+func saveCache(dir, group, service string, items []*registry.ServiceInstance) error {
+	b, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".nacos-cache-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err = f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), cacheFile(dir, group, service))
+}
+
+// loadCache reads the instances from the cache file.
+func loadCache(dir, group, service string) ([]*registry.ServiceInstance, error) {
+	b, err := os.ReadFile(cacheFile(dir, group, service))
+	if err != nil {
+		return nil, err
+	}
+	var items []*registry.ServiceInstance
+	if err = json.Unmarshal(b, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
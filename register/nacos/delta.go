@@ -0,0 +1,57 @@
+package nacos
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+)
+
+// Delta is the change of the instances of a watched service.
+type Delta struct {
+	Service string
+	Added   []*registry.ServiceInstance
+	Removed []*registry.ServiceInstance
+	// Updated are the instances whose version, endpoints or metadata changed.
+	Updated []*registry.ServiceInstance
+}
+
+// Empty reports whether nothing changed.
+func (d Delta) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Updated) == 0
+}
+
+// DeltaHandler handles the delta of watched services.
+type DeltaHandler func(d Delta)
+
+// diff compares the instances by ID.
+func diff(service string, old, cur []*registry.ServiceInstance) Delta {
+	d := Delta{Service: service}
+	before := make(map[string]*registry.ServiceInstance, len(old))
+	for _, si := range old {
+		before[si.ID] = si
+	}
+	for _, si := range cur {
+		prev, ok := before[si.ID]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, si)
+		case !sameInstance(prev, si):
+			d.Updated = append(d.Updated, si)
+		}
+		delete(before, si.ID)
+	}
+	for _, si := range old {
+		if _, ok := before[si.ID]; ok {
+			d.Removed = append(d.Removed, si)
+		}
+	}
+	return d
+}
+
+func sameInstance(a, b *registry.ServiceInstance) bool {
+	return a.Name == b.Name &&
+		a.Version == b.Version &&
+		slices.Equal(a.Endpoints, b.Endpoints) &&
+		maps.Equal(a.Metadata, b.Metadata)
+}
@@ -0,0 +1,105 @@
+package nacos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jiushengTech/common/register"
+	"github.com/jiushengTech/kratos/v2/registry"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+)
+
+// metadataID is the ServiceInstance.ID in the metadata of every endpoint, used to aggregate the endpoints.
+const metadataID = "kratos.id"
+
+// target is a nacos service of a kratos service.
+type target struct {
+	service string
+	scheme  string
+}
+
+// serviceName returns the nacos service name of an endpoint.
+func (r *Registry) serviceName(name, scheme string) string {
+	return r.prefixed(name) + "." + scheme
+}
+
+func (r *Registry) prefixed(name string) string {
+	if p := strings.Trim(r.opts.prefix, "/"); p != "" {
+		return p + "." + name
+	}
+	return name
+}
+
+// targets returns the nacos services of a kratos service.
+// The service without scheme suffix is included for instances registered by other frameworks.
+func (r *Registry) targets(serviceName string) (string, []target) {
+	for _, s := range r.opts.schemes {
+		if name, ok := strings.CutSuffix(serviceName, "."+s); ok {
+			return name, []target{{service: r.serviceName(name, s), scheme: s}}
+		}
+	}
+	targets := make([]target, 0, len(r.opts.schemes)+1)
+	for _, s := range r.opts.schemes {
+		targets = append(targets, target{service: r.serviceName(serviceName, s), scheme: s})
+	}
+	targets = append(targets, target{service: r.prefixed(serviceName), scheme: r.opts.kind})
+	return serviceName, targets
+}
+
+// aggregate merges the endpoints registered by the same ServiceInstance.
+// hosts[i] are the instances of targets[i].
+func aggregate(name string, targets []target, hosts [][]model.Instance, healthyOnly bool) []*registry.ServiceInstance {
+	items := make([]*registry.ServiceInstance, 0)
+	byID := make(map[string]*registry.ServiceInstance)
+	for i, t := range targets {
+		for _, in := range hosts[i] {
+			if healthyOnly && (!in.Healthy || !in.Enable || in.Weight <= 0) {
+				continue
+			}
+			si := toServiceInstance(in, name, t.scheme)
+			id, ok := si.Metadata[metadataID]
+			if !ok {
+				items = append(items, si)
+				continue
+			}
+			delete(si.Metadata, metadataID)
+			si.ID = id
+			prev, ok := byID[id]
+			if !ok {
+				byID[id] = si
+				items = append(items, si)
+				continue
+			}
+			prev.Endpoints = append(prev.Endpoints, si.Endpoints...)
+			if si.Metadata[register.MetadataHealthy] == "false" {
+				prev.Metadata[register.MetadataHealthy] = "false"
+			}
+		}
+	}
+	return items
+}
+
+// toServiceInstance converts a nacos instance, the weight, cluster, health and zone
+// of the instance are copied into metadata with the keys defined in package register.
+func toServiceInstance(in model.Instance, name, defaultKind string) *registry.ServiceInstance {
+	md := make(map[string]string, len(in.Metadata)+4)
+	for k, v := range in.Metadata {
+		md[k] = v
+	}
+	md[register.MetadataWeight] = strconv.FormatFloat(in.Weight, 'f', -1, 64)
+	md[register.MetadataCluster] = in.ClusterName
+	md[register.MetadataHealthy] = strconv.FormatBool(in.Healthy && in.Enable)
+
+	kind := defaultKind
+	if k, ok := md[register.MetadataKind]; ok {
+		kind = k
+	}
+	return &registry.ServiceInstance{
+		ID:        in.InstanceId,
+		Name:      name,
+		Version:   md[register.MetadataVersion],
+		Metadata:  md,
+		Endpoints: []string{fmt.Sprintf("%s://%s:%d", kind, in.Ip, in.Port)},
+	}
+}
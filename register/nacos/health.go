@@ -0,0 +1,121 @@
+package nacos
+
+import (
+	"context"
+	"time"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// HealthCheck reports whether the service instance is healthy, a non-nil error means unhealthy.
+type HealthCheck func(ctx context.Context) error
+
+// instance is a service instance registered by this registry.
+type instance struct {
+	params  []vo.RegisterInstanceParam
+	healthy bool
+	cancel  context.CancelFunc
+}
+
+func instanceKey(si *registry.ServiceInstance) string {
+	return si.Name + "#" + si.ID
+}
+
+// track records the registered instance and starts its heartbeat when check is not nil.
+func (r *Registry) track(si *registry.ServiceInstance, params []vo.RegisterInstanceParam, check HealthCheck) {
+	ins := &instance{params: params, healthy: true}
+
+	r.mu.Lock()
+	if old, ok := r.instances[instanceKey(si)]; ok && old.cancel != nil {
+		old.cancel()
+	}
+	r.instances[instanceKey(si)] = ins
+	if check != nil && r.opts.healthInterval > 0 && len(params) > 0 {
+		var ctx context.Context
+		ctx, ins.cancel = context.WithCancel(context.Background())
+		go r.heartbeat(ctx, ins, check)
+	}
+	r.mu.Unlock()
+}
+
+// untrack stops the heartbeat of the instance.
+func (r *Registry) untrack(si *registry.ServiceInstance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ins, ok := r.instances[instanceKey(si)]; ok {
+		if ins.cancel != nil {
+			ins.cancel()
+		}
+		delete(r.instances, instanceKey(si))
+	}
+}
+
+// heartbeat runs check periodically and reports health changes to nacos.
+func (r *Registry) heartbeat(ctx context.Context, ins *instance, check HealthCheck) {
+	ticker := time.NewTicker(r.opts.healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, r.opts.healthInterval)
+		err := check(checkCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		healthy := err == nil
+
+		r.mu.Lock()
+		changed := ins.healthy != healthy
+		ins.healthy = healthy
+		params := ins.params
+		r.mu.Unlock()
+		if !changed {
+			continue
+		}
+
+		if healthy {
+			r.log.Infof("nacos: instance %s recovered", params[0].ServiceName)
+		} else {
+			r.log.Warnf("nacos: instance %s health check failed: %v", params[0].ServiceName, err)
+		}
+		if e := r.updateInstances(params, healthy); e != nil {
+			r.log.Errorf("nacos: update instance health err: %v", e)
+			// 上报失败时恢复原状态，下一次检查会重新上报
+			r.mu.Lock()
+			ins.healthy = !healthy
+			r.mu.Unlock()
+		}
+	}
+}
+
+// updateInstances updates every endpoint of an instance with the given health.
+// Depending on WithDisableOnUnhealthy the instance is marked unhealthy or disabled.
+func (r *Registry) updateInstances(params []vo.RegisterInstanceParam, healthy bool) error {
+	for _, p := range params {
+		enable := true
+		if !healthy && r.opts.disableOnUnhealthy {
+			enable = false
+		}
+		if _, err := r.cli.UpdateInstance(vo.UpdateInstanceParam{
+			Ip:          p.Ip,
+			Port:        p.Port,
+			Weight:      p.Weight,
+			Enable:      enable,
+			Healthy:     healthy || r.opts.disableOnUnhealthy,
+			Metadata:    p.Metadata,
+			ClusterName: p.ClusterName,
+			ServiceName: p.ServiceName,
+			GroupName:   p.GroupName,
+			Ephemeral:   p.Ephemeral,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
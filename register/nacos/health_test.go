@@ -0,0 +1,168 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+)
+
+// waitFor polls cond until it returns true or the timeout expires.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not satisfied before timeout")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRegistry_HealthCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		disable     bool
+		wantEnable  bool
+		wantHealthy bool
+	}{
+		{name: "markUnhealthy", disable: false, wantEnable: true, wantHealthy: false},
+		{name: "disable", disable: true, wantEnable: false, wantHealthy: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newFakeNamingClient()
+			var failing atomic.Bool
+			r := New(cli,
+				WithHealthCheckInterval(10*time.Millisecond),
+				WithDisableOnUnhealthy(tt.disable),
+				WithHealthCheck(func(ctx context.Context) error {
+					if failing.Load() {
+						return errors.New("db down")
+					}
+					return nil
+				}),
+			)
+			si := &registry.ServiceInstance{
+				ID:        "1",
+				Name:      "health",
+				Version:   "v1.0.0",
+				Endpoints: []string{"grpc://127.0.0.1:9000"},
+			}
+			if err := r.Register(context.Background(), si); err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = r.Deregister(context.Background(), si) }()
+
+			failing.Store(true)
+			waitFor(t, time.Second, func() bool {
+				_, n := cli.lastUpdate()
+				return n == 1
+			})
+			in := cli.instances("DEFAULT_GROUP", "health.grpc")[0]
+			if in.Enable != tt.wantEnable || in.Healthy != tt.wantHealthy {
+				t.Errorf("expect enable=%v healthy=%v, got enable=%v healthy=%v", tt.wantEnable, tt.wantHealthy, in.Enable, in.Healthy)
+			}
+
+			failing.Store(false)
+			waitFor(t, time.Second, func() bool {
+				_, n := cli.lastUpdate()
+				return n == 2
+			})
+			in = cli.instances("DEFAULT_GROUP", "health.grpc")[0]
+			if !in.Enable || !in.Healthy {
+				t.Errorf("expect recovered instance, got enable=%v healthy=%v", in.Enable, in.Healthy)
+			}
+		})
+	}
+}
+
+func TestRegistry_RegisterWithHealthCheckStopsOnDeregister(t *testing.T) {
+	cli := newFakeNamingClient()
+	var calls atomic.Int32
+	r := New(cli, WithHealthCheckInterval(5*time.Millisecond))
+	si := &registry.ServiceInstance{
+		ID:        "1",
+		Name:      "stop",
+		Endpoints: []string{"http://127.0.0.1:8000"},
+	}
+	err := r.RegisterWithHealthCheck(context.Background(), si, func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, time.Second, func() bool { return calls.Load() > 0 })
+	if err = r.Deregister(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	n := calls.Load()
+	time.Sleep(30 * time.Millisecond)
+	if calls.Load() != n {
+		t.Errorf("health check should stop after Deregister")
+	}
+}
+
+func TestRegistry_Persistent(t *testing.T) {
+	cli := newFakeNamingClient()
+	r := New(cli, WithEphemeral(false))
+	si := &registry.ServiceInstance{
+		ID:        "1",
+		Name:      "persistent",
+		Endpoints: []string{"grpc://127.0.0.1:9000"},
+	}
+	if err := r.Register(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	if in := cli.instances("DEFAULT_GROUP", "persistent.grpc")[0]; in.Ephemeral {
+		t.Errorf("expect persistent instance")
+	}
+	if err := r.Deregister(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(cli.instances("DEFAULT_GROUP", "persistent.grpc")); n != 0 {
+		t.Errorf("expect 0 instances after Deregister, got %d", n)
+	}
+}
+
+func TestRegistry_Update(t *testing.T) {
+	cli := newFakeNamingClient()
+	r := New(cli)
+	si := &registry.ServiceInstance{
+		ID:        "1",
+		Name:      "update",
+		Version:   "v1.0.0",
+		Endpoints: []string{"grpc://127.0.0.1:9000"},
+	}
+	if err := r.Update(context.Background(), si); !errors.Is(err, ErrServiceInstanceNotRegistered) {
+		t.Fatalf("expect ErrServiceInstanceNotRegistered, got %v", err)
+	}
+	if err := r.Register(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	if in := cli.instances("DEFAULT_GROUP", "update.grpc")[0]; in.Weight != 100 {
+		t.Errorf("expect default weight 100, got %v", in.Weight)
+	}
+
+	si.Metadata = map[string]string{"weight": "30", "idc": "shanghai"}
+	if err := r.Update(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	in := cli.instances("DEFAULT_GROUP", "update.grpc")[0]
+	if in.Weight != 30 {
+		t.Errorf("expect weight 30, got %v", in.Weight)
+	}
+	if in.Metadata["idc"] != "shanghai" || in.Metadata["version"] != "v1.0.0" {
+		t.Errorf("unexpected metadata %v", in.Metadata)
+	}
+
+	si.Metadata["weight"] = "heavy"
+	if err := r.Update(context.Background(), si); err == nil {
+		t.Errorf("expect error for invalid weight")
+	}
+}
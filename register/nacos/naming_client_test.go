@@ -0,0 +1,268 @@
+package nacos
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+var _ naming_client.INamingClient = (*fakeNamingClient)(nil)
+
+var errFakeUnavailable = errors.New("fake nacos: server unavailable")
+
+// fakeNamingClient is an in-memory INamingClient, mimicking the naming rules of nacos.
+type fakeNamingClient struct {
+	naming_client.INamingClient
+
+	mu          sync.Mutex
+	services    map[string]map[string]model.Instance // group@@service -> instanceId -> instance
+	subscribers map[string][]*vo.SubscribeParam
+	updates     []vo.UpdateInstanceParam
+	unavailable bool
+	// subscribeErrs fails the subscriptions of the services
+	subscribeErrs map[string]error
+	queries     int
+}
+
+func newFakeNamingClient() *fakeNamingClient {
+	return &fakeNamingClient{
+		services:    make(map[string]map[string]model.Instance),
+		subscribers: make(map[string][]*vo.SubscribeParam),
+	}
+}
+
+func groupedName(group, service string) string {
+	if group == "" {
+		group = constant.DEFAULT_GROUP
+	}
+	return group + constant.SERVICE_INFO_SPLITER + service
+}
+
+func (c *fakeNamingClient) setUnavailable(unavailable bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unavailable = unavailable
+}
+
+func (c *fakeNamingClient) instances(group, service string) []model.Instance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.instancesLocked(groupedName(group, service))
+}
+
+func (c *fakeNamingClient) instancesLocked(key string) []model.Instance {
+	res := make([]model.Instance, 0, len(c.services[key]))
+	for _, in := range c.services[key] {
+		res = append(res, in)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].InstanceId < res[j].InstanceId })
+	return res
+}
+
+// notify calls subscribers of the service, like nacos does after a change.
+func (c *fakeNamingClient) notify(key string) {
+	c.mu.Lock()
+	subs := append([]*vo.SubscribeParam(nil), c.subscribers[key]...)
+	hosts := c.instancesLocked(key)
+	c.mu.Unlock()
+	for _, s := range subs {
+		s.SubscribeCallback(hosts, nil)
+	}
+}
+
+func (c *fakeNamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	c.mu.Lock()
+	if c.unavailable {
+		c.mu.Unlock()
+		return false, errFakeUnavailable
+	}
+	key := groupedName(param.GroupName, param.ServiceName)
+	if c.services[key] == nil {
+		c.services[key] = make(map[string]model.Instance)
+	}
+	id := fmt.Sprintf("%s#%d#%s#%s", param.Ip, param.Port, param.ClusterName, key)
+	c.services[key][id] = model.Instance{
+		InstanceId:  id,
+		Ip:          param.Ip,
+		Port:        param.Port,
+		Weight:      param.Weight,
+		Healthy:     param.Healthy,
+		Enable:      param.Enable,
+		Ephemeral:   param.Ephemeral,
+		ClusterName: param.ClusterName,
+		ServiceName: key,
+		Metadata:    param.Metadata,
+	}
+	c.mu.Unlock()
+	c.notify(key)
+	return true, nil
+}
+
+func (c *fakeNamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+	c.mu.Lock()
+	if c.unavailable {
+		c.mu.Unlock()
+		return false, errFakeUnavailable
+	}
+	key := groupedName(param.GroupName, param.ServiceName)
+	id := fmt.Sprintf("%s#%d#%s#%s", param.Ip, param.Port, param.Cluster, key)
+	if _, ok := c.services[key][id]; !ok {
+		c.mu.Unlock()
+		return false, fmt.Errorf("fake nacos: instance %s not found", id)
+	}
+	delete(c.services[key], id)
+	c.mu.Unlock()
+	c.notify(key)
+	return true, nil
+}
+
+func (c *fakeNamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	c.mu.Lock()
+	if c.unavailable {
+		c.mu.Unlock()
+		return false, errFakeUnavailable
+	}
+	c.updates = append(c.updates, param)
+	key := groupedName(param.GroupName, param.ServiceName)
+	id := fmt.Sprintf("%s#%d#%s#%s", param.Ip, param.Port, param.ClusterName, key)
+	in, ok := c.services[key][id]
+	if !ok {
+		c.mu.Unlock()
+		return false, fmt.Errorf("fake nacos: instance %s not found", id)
+	}
+	in.Weight = param.Weight
+	in.Enable = param.Enable
+	in.Healthy = param.Healthy
+	in.Metadata = param.Metadata
+	c.services[key][id] = in
+	c.mu.Unlock()
+	c.notify(key)
+	return true, nil
+}
+
+func (c *fakeNamingClient) lastUpdate() (vo.UpdateInstanceParam, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.updates) == 0 {
+		return vo.UpdateInstanceParam{}, 0
+	}
+	return c.updates[len(c.updates)-1], len(c.updates)
+}
+
+// pushError calls subscribers of the service with err.
+func (c *fakeNamingClient) pushError(group, service string, err error) {
+	c.mu.Lock()
+	subs := append([]*vo.SubscribeParam(nil), c.subscribers[groupedName(group, service)]...)
+	c.mu.Unlock()
+	for _, s := range subs {
+		s.SubscribeCallback(nil, err)
+	}
+}
+
+func (c *fakeNamingClient) queryCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.queries
+}
+
+func (c *fakeNamingClient) GetService(param vo.GetServiceParam) (model.Service, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries++
+	if c.unavailable {
+		return model.Service{}, errFakeUnavailable
+	}
+	key := groupedName(param.GroupName, param.ServiceName)
+	return model.Service{
+		Name:      key,
+		GroupName: param.GroupName,
+		Hosts:     c.instancesLocked(key),
+		Valid:     true,
+	}, nil
+}
+
+func (c *fakeNamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unavailable {
+		return nil, errFakeUnavailable
+	}
+	return c.instancesLocked(groupedName(param.GroupName, param.ServiceName)), nil
+}
+
+func (c *fakeNamingClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unavailable {
+		return nil, errFakeUnavailable
+	}
+	key := groupedName(param.GroupName, param.ServiceName)
+	all := c.instancesLocked(key)
+	res := make([]model.Instance, 0, len(all))
+	for _, in := range all {
+		if in.Enable && in.Healthy == param.HealthyOnly && in.Weight > 0 {
+			res = append(res, in)
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("instance list is empty")
+	}
+	return res, nil
+}
+
+func (c *fakeNamingClient) Subscribe(param *vo.SubscribeParam) error {
+	key := groupedName(param.GroupName, param.ServiceName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unavailable {
+		return errFakeUnavailable
+	}
+	if err := c.subscribeErrs[param.ServiceName]; err != nil {
+		return err
+	}
+	c.subscribers[key] = append(c.subscribers[key], param)
+	return nil
+}
+
+func (c *fakeNamingClient) setSubscribeErr(service string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subscribeErrs == nil {
+		c.subscribeErrs = make(map[string]error)
+	}
+	c.subscribeErrs[service] = err
+}
+
+func (c *fakeNamingClient) subscriberCount(group, service string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.subscribers[groupedName(group, service)])
+}
+
+func (c *fakeNamingClient) Unsubscribe(param *vo.SubscribeParam) error {
+	key := groupedName(param.GroupName, param.ServiceName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	subs := c.subscribers[key]
+	for i, s := range subs {
+		if s == param {
+			c.subscribers[key] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (c *fakeNamingClient) ServerHealthy() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.unavailable
+}
+
+func (c *fakeNamingClient) CloseClient() {}
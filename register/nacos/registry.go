@@ -7,14 +7,24 @@ import (
 	"net"
 	"net/url"
 	"strconv"
+	"sync"
+	"time"
 
+	klog "github.com/go-kratos/kratos/v2/log"
+	"github.com/jiushengTech/common/log"
+	"github.com/jiushengTech/common/register"
 	"github.com/jiushengTech/kratos/v2/registry"
 	"github.com/nacos-group/nacos-sdk-go/v2/clients/naming_client"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
 )
 
-var ErrServiceInstanceNameEmpty = errors.New("kratos/nacos: ServiceInstance.Name can not be empty")
+var (
+	ErrServiceInstanceNameEmpty     = errors.New("kratos/nacos: ServiceInstance.Name can not be empty")
+	ErrServiceInstanceNotRegistered = errors.New("kratos/nacos: ServiceInstance is not registered")
+	ErrServiceNotFound              = errors.New("kratos/nacos: service not found")
+)
 
 var (
 	_ registry.Registrar = (*Registry)(nil)
@@ -27,12 +37,25 @@ type options struct {
 	cluster string
 	group   string
 	kind    string
+	zone    string
+	schemes []string
+
+	ephemeral          bool
+	healthCheck        HealthCheck
+	healthInterval     time.Duration
+	disableOnUnhealthy bool
+	keepUnhealthy      bool
+	logger             log.Logger
+
+	cacheDir     string
+	deltaHandler DeltaHandler
 }
 
 // Option is nacos option.
 type Option func(o *options)
 
-// WithPrefix with prefix path.
+// WithPrefix with prefix option, the nacos service name is prefix.name.scheme.
+// Slashes are trimmed, so WithPrefix("/microservices") registers microservices.helloworld.grpc.
 func WithPrefix(prefix string) Option {
 	return func(o *options) { o.prefix = prefix }
 }
@@ -52,41 +75,154 @@ func WithGroup(group string) Option {
 	return func(o *options) { o.group = group }
 }
 
+// WithSchemes with schemes option, the schemes aggregated by GetService and Watch.
+func WithSchemes(schemes ...string) Option {
+	return func(o *options) { o.schemes = schemes }
+}
+
 // WithDefaultKind with default kind option.
 func WithDefaultKind(kind string) Option {
 	return func(o *options) { o.kind = kind }
 }
 
+// WithZone with zone option, the zone is registered in metadata unless the instance sets its own.
+func WithZone(zone string) Option {
+	return func(o *options) { o.zone = zone }
+}
+
+// WithEphemeral with ephemeral option, false registers persistent instances.
+func WithEphemeral(ephemeral bool) Option {
+	return func(o *options) { o.ephemeral = ephemeral }
+}
+
+// WithHealthCheck with default health check for every registered instance.
+func WithHealthCheck(check HealthCheck) Option {
+	return func(o *options) { o.healthCheck = check }
+}
+
+// WithHealthCheckInterval with health check interval option.
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(o *options) { o.healthInterval = interval }
+}
+
+// WithDisableOnUnhealthy with disable on unhealthy option.
+// The instance is disabled instead of marked unhealthy when the health check fails.
+func WithDisableOnUnhealthy(disable bool) Option {
+	return func(o *options) { o.disableOnUnhealthy = disable }
+}
+
+// WithUnhealthyInstances with unhealthy instances option.
+// By default GetService and Watch drop the unhealthy, disabled and zero weight instances,
+// as the kratos selectors route to every node. When keep is true they are returned with
+// the healthy metadata set to false, for the selectors skipping them such as register/selector.
+func WithUnhealthyInstances(keep bool) Option {
+	return func(o *options) { o.keepUnhealthy = keep }
+}
+
+// WithLogger with logger option.
+func WithLogger(logger log.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithCacheDir with cache dir option.
+// Watchers persist the last known instances in the dir and use them on cold starts when nacos is unreachable.
+func WithCacheDir(dir string) Option {
+	return func(o *options) { o.cacheDir = dir }
+}
+
+// WithDeltaHandler with delta handler option, called by watchers with the instances changed.
+func WithDeltaHandler(handler DeltaHandler) Option {
+	return func(o *options) { o.deltaHandler = handler }
+}
+
 // Registry is nacos registry.
 type Registry struct {
 	opts options
 	cli  naming_client.INamingClient
+	log  *klog.Helper
+
+	mu        sync.Mutex
+	instances map[string]*instance
 }
 
 // New new a nacos registry.
 func New(cli naming_client.INamingClient, opts ...Option) (r *Registry) {
 	op := options{
-		prefix:  "/microservices",
+		prefix:  "",
 		cluster: "DEFAULT",
 		group:   constant.DEFAULT_GROUP,
 		weight:  100,
 		kind:    "grpc",
+		schemes: []string{"grpc", "http"},
+
+		ephemeral:      true,
+		healthInterval: 5 * time.Second,
+		logger:         log.GetLogger(),
 	}
 	for _, option := range opts {
 		option(&op)
 	}
 	return &Registry{
-		opts: op,
-		cli:  cli,
+		opts:      op,
+		cli:       cli,
+		log:       log.NewHelper(op.logger),
+		instances: make(map[string]*instance),
 	}
 }
 
 // Register the registration.
-func (r *Registry) Register(_ context.Context, si *registry.ServiceInstance) error {
+// The health check configured by WithHealthCheck is applied to the instance.
+func (r *Registry) Register(ctx context.Context, si *registry.ServiceInstance) error {
+	return r.RegisterWithHealthCheck(ctx, si, r.opts.healthCheck)
+}
+
+// RegisterWithHealthCheck registers the instance and reports the result of check to nacos periodically.
+// A nil check registers the instance as always healthy.
+func (r *Registry) RegisterWithHealthCheck(_ context.Context, si *registry.ServiceInstance, check HealthCheck) error {
 	if si.Name == "" {
 		return ErrServiceInstanceNameEmpty
 	}
-	for _, endpoint := range si.Endpoints {
+	params, err := r.registerParams(si)
+	if err != nil {
+		return err
+	}
+	for _, param := range params {
+		if _, e := r.cli.RegisterInstance(param); e != nil {
+			return fmt.Errorf("RegisterInstance err %v,%v", e, param.ServiceName)
+		}
+	}
+	r.track(si, params, check)
+	return nil
+}
+
+// Update updates weight and metadata of a registered instance at runtime.
+// The weight is read from si.Metadata["weight"], falling back to WithWeight.
+func (r *Registry) Update(_ context.Context, si *registry.ServiceInstance) error {
+	if si.Name == "" {
+		return ErrServiceInstanceNameEmpty
+	}
+	params, err := r.registerParams(si)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	ins, ok := r.instances[instanceKey(si)]
+	if !ok {
+		r.mu.Unlock()
+		return ErrServiceInstanceNotRegistered
+	}
+	ins.params = params
+	healthy := ins.healthy
+	r.mu.Unlock()
+
+	return r.updateInstances(params, healthy)
+}
+
+// Deregister the registration.
+func (r *Registry) Deregister(_ context.Context, service *registry.ServiceInstance) error {
+	r.untrack(service)
+	for _, endpoint := range service.Endpoints {
 		u, err := url.Parse(endpoint)
 		if err != nil {
 			return err
@@ -99,96 +235,106 @@ func (r *Registry) Register(_ context.Context, si *registry.ServiceInstance) err
 		if err != nil {
 			return err
 		}
-		var rmd map[string]string
-		if si.Metadata == nil {
-			rmd = map[string]string{
-				"kind":    u.Scheme,
-				"version": si.Version,
-			}
-		} else {
-			rmd = make(map[string]string, len(si.Metadata)+2)
-			for k, v := range si.Metadata {
-				rmd[k] = v
-			}
-			rmd["kind"] = u.Scheme
-			rmd["version"] = si.Version
-		}
-		_, e := r.cli.RegisterInstance(vo.RegisterInstanceParam{
+		if _, err = r.cli.DeregisterInstance(vo.DeregisterInstanceParam{
 			Ip:          host,
 			Port:        uint64(p),
-			ServiceName: si.Name + "." + u.Scheme,
-			Weight:      r.opts.weight,
-			Enable:      true,
-			Healthy:     true,
-			Ephemeral:   true,
-			Metadata:    rmd,
-			ClusterName: r.opts.cluster,
+			ServiceName: r.serviceName(service.Name, u.Scheme),
 			GroupName:   r.opts.group,
-		})
-		if e != nil {
-			return fmt.Errorf("RegisterInstance err %v,%v", e, endpoint)
+			Cluster:     r.opts.cluster,
+			Ephemeral:   r.opts.ephemeral,
+		}); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-// Deregister the registration.
-func (r *Registry) Deregister(_ context.Context, service *registry.ServiceInstance) error {
-	for _, endpoint := range service.Endpoints {
+// registerParams builds one nacos instance per endpoint.
+func (r *Registry) registerParams(si *registry.ServiceInstance) ([]vo.RegisterInstanceParam, error) {
+	weight := r.opts.weight
+	if w, ok := si.Metadata[register.MetadataWeight]; ok {
+		v, err := strconv.ParseFloat(w, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight metadata %q: %w", w, err)
+		}
+		weight = v
+	}
+	params := make([]vo.RegisterInstanceParam, 0, len(si.Endpoints))
+	for _, endpoint := range si.Endpoints {
 		u, err := url.Parse(endpoint)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		host, port, err := net.SplitHostPort(u.Host)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		p, err := strconv.Atoi(port)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if _, err = r.cli.DeregisterInstance(vo.DeregisterInstanceParam{
+		rmd := make(map[string]string, len(si.Metadata)+4)
+		for k, v := range si.Metadata {
+			rmd[k] = v
+		}
+		rmd[register.MetadataKind] = u.Scheme
+		rmd[register.MetadataVersion] = si.Version
+		if si.ID != "" {
+			rmd[metadataID] = si.ID
+		}
+		if _, ok := rmd[register.MetadataZone]; !ok && r.opts.zone != "" {
+			rmd[register.MetadataZone] = r.opts.zone
+		}
+		params = append(params, vo.RegisterInstanceParam{
 			Ip:          host,
 			Port:        uint64(p),
-			ServiceName: service.Name + "." + u.Scheme,
+			ServiceName: r.serviceName(si.Name, u.Scheme),
+			Weight:      weight,
+			Enable:      true,
+			Healthy:     true,
+			Ephemeral:   r.opts.ephemeral,
+			Metadata:    rmd,
+			ClusterName: r.opts.cluster,
 			GroupName:   r.opts.group,
-			Cluster:     r.opts.cluster,
-			Ephemeral:   true,
-		}); err != nil {
-			return err
-		}
+		})
 	}
-	return nil
+	return params, nil
 }
 
 // Watch creates a watcher according to the service name.
+// The service name is the kratos app name, the nacos services of every scheme (WithSchemes) are
+// watched and aggregated, so each ServiceInstance has the endpoints of all its schemes.
+// A service name ending with a scheme, e.g. helloworld.grpc, watches the service of that scheme only.
+// The schemes failing to subscribe are retried in the background while the others are watched.
+// If nacos can't be subscribed at all, the disk cache (WithCacheDir) is used and the subscription is retried
+// in the background, and without cached instances the error is returned.
 func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
-	return newWatcher(ctx, r.cli, serviceName, r.opts.group, r.opts.kind, []string{r.opts.cluster})
+	w, err := newWatcher(ctx, r, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
 }
 
 // GetService return the service instances in memory according to the service name.
+// The instances of every scheme are aggregated, see Watch.
 func (r *Registry) GetService(_ context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
-	res, err := r.cli.SelectInstances(vo.SelectInstancesParam{
-		ServiceName: serviceName,
-		GroupName:   r.opts.group,
-		HealthyOnly: true,
-	})
-	if err != nil {
-		return nil, err
-	}
-	items := make([]*registry.ServiceInstance, 0, len(res))
-	for _, in := range res {
-		kind := r.opts.kind
-		if k, ok := in.Metadata["kind"]; ok {
-			kind = k
-		}
-		items = append(items, &registry.ServiceInstance{
-			ID:        in.InstanceId,
-			Name:      in.ServiceName,
-			Version:   in.Metadata["version"],
-			Metadata:  in.Metadata,
-			Endpoints: []string{fmt.Sprintf("%s://%s:%d", kind, in.Ip, in.Port)},
+	name, targets := r.targets(serviceName)
+	hosts := make([][]model.Instance, len(targets))
+	found := false
+	for i, t := range targets {
+		res, err := r.cli.SelectAllInstances(vo.SelectAllInstancesParam{
+			ServiceName: t.service,
+			GroupName:   r.opts.group,
 		})
+		if err != nil {
+			return nil, err
+		}
+		hosts[i] = res
+		found = found || len(res) > 0
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceName)
 	}
-	return items, nil
+	return aggregate(name, targets, hosts, !r.opts.keepUnhealthy), nil
 }
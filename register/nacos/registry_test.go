@@ -394,10 +394,16 @@ func TestRegistry_GetService(t *testing.T) {
 				serviceName: testServer.Name + "." + "grpc",
 			},
 			want: []*registry.ServiceInstance{{
-				ID:        "127.0.0.1#8080#DEFAULT#DEFAULT_GROUP@@test3.grpc",
-				Name:      "DEFAULT_GROUP@@test3.grpc",
-				Version:   "v1.0.0",
-				Metadata:  map[string]string{"version": "v1.0.0", "kind": "grpc"},
+				ID:      "1",
+				Name:    "test3",
+				Version: "v1.0.0",
+				Metadata: map[string]string{
+					"version": "v1.0.0",
+					"kind":    "grpc",
+					"weight":  "100",
+					"cluster": "DEFAULT",
+					"healthy": "true",
+				},
 				Endpoints: []string{"grpc://127.0.0.1:8080"},
 			}},
 			wantErr: false,
@@ -497,10 +503,16 @@ func TestRegistry_Watch(t *testing.T) {
 			},
 			wantErr: false,
 			want: []*registry.ServiceInstance{{
-				ID:        "127.0.0.1#8080#DEFAULT#DEFAULT_GROUP@@test4.grpc",
-				Name:      "DEFAULT_GROUP@@test4.grpc",
-				Version:   "v1.0.0",
-				Metadata:  map[string]string{"version": "v1.0.0", "kind": "grpc"},
+				ID:      "1",
+				Name:    "test4",
+				Version: "v1.0.0",
+				Metadata: map[string]string{
+					"version": "v1.0.0",
+					"kind":    "grpc",
+					"weight":  "100",
+					"cluster": "DEFAULT",
+					"healthy": "true",
+				},
 				Endpoints: []string{"grpc://127.0.0.1:8080"},
 			}},
 			processFunc: func(t *testing.T) {
@@ -2,8 +2,12 @@ package nacos
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	klog "github.com/go-kratos/kratos/v2/log"
 	"github.com/nacos-group/nacos-sdk-go/v2/clients/naming_client"
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
@@ -13,81 +17,278 @@ import (
 
 var _ registry.Watcher = (*watcher)(nil)
 
+// retryInterval is the interval to query or subscribe nacos again while the disk cache is used.
+var retryInterval = 3 * time.Second
+
 type watcher struct {
-	serviceName    string
-	clusters       []string
-	groupName      string
-	ctx            context.Context
-	cancel         context.CancelFunc
-	watchChan      chan struct{}
-	cli            naming_client.INamingClient
-	kind           string
-	subscribeParam *vo.SubscribeParam
+	serviceName     string
+	name            string
+	targets         []target
+	clusters        []string
+	groupName       string
+	ctx             context.Context
+	cancel          context.CancelFunc
+	watchChan       chan struct{}
+	cli             naming_client.INamingClient
+	subscribeParams []*vo.SubscribeParam
+	cacheDir        string
+	deltaHandler    DeltaHandler
+	healthyOnly     bool
+	log             *klog.Helper
+
+	// subMu serializes subscribing and Stop, subscribed[i] is true once targets[i] is subscribed
+	subMu      sync.Mutex
+	subscribed []bool
+
+	mu sync.Mutex
+	// hosts[i] are the instances of targets[i], known[i] is true once they come from nacos
+	hosts     [][]model.Instance
+	known     []bool
+	instances []*registry.ServiceInstance
+	// synced is true once instances of all targets come from nacos
+	synced bool
 }
 
-func newWatcher(ctx context.Context, cli naming_client.INamingClient, serviceName, groupName, kind string, clusters []string) (*watcher, error) {
+func newWatcher(ctx context.Context, r *Registry, serviceName string) (*watcher, error) {
+	name, targets := r.targets(serviceName)
 	w := &watcher{
-		serviceName: serviceName,
-		clusters:    clusters,
-		groupName:   groupName,
-		cli:         cli,
-		kind:        kind,
-		watchChan:   make(chan struct{}, 1),
+		serviceName:  serviceName,
+		name:         name,
+		targets:      targets,
+		clusters:     []string{r.opts.cluster},
+		groupName:    r.opts.group,
+		cli:          r.cli,
+		watchChan:    make(chan struct{}, 1),
+		cacheDir:     r.opts.cacheDir,
+		deltaHandler: r.opts.deltaHandler,
+		healthyOnly:  !r.opts.keepUnhealthy,
+		log:          r.log,
+		hosts:        make([][]model.Instance, len(targets)),
+		known:        make([]bool, len(targets)),
+		subscribed:   make([]bool, len(targets)),
 	}
 	w.ctx, w.cancel = context.WithCancel(ctx)
 
-	w.subscribeParam = &vo.SubscribeParam{
-		ServiceName: serviceName,
-		Clusters:    clusters,
-		GroupName:   groupName,
-		SubscribeCallback: func(services []model.Instance, err error) {
-			select {
-			case w.watchChan <- struct{}{}:
-			default:
-			}
-		},
-	}
-	e := w.cli.Subscribe(w.subscribeParam)
+	all := make([]int, len(targets))
+	for i, t := range targets {
+		i := i
+		all[i] = i
+		w.subscribeParams = append(w.subscribeParams, &vo.SubscribeParam{
+			ServiceName: t.service,
+			Clusters:    w.clusters,
+			GroupName:   w.groupName,
+			SubscribeCallback: func(services []model.Instance, err error) {
+				w.onChange(i, services, err)
+			},
+		})
+	}
+	failed, err := w.subscribe(all)
+	switch {
+	case len(failed) == 0:
+	case len(failed) < len(targets):
+		// the working schemes are served, the failed ones have no instances until subscribed
+		w.mu.Lock()
+		for _, i := range failed {
+			w.known[i] = true
+		}
+		w.mu.Unlock()
+		w.log.Warnf("nacos: subscribe %s err, watch the other schemes and retry: %v", serviceName, err)
+		go w.resubscribe(failed)
+	case w.cached():
+		// the disk cache serves until nacos is reachable again
+		w.log.Warnf("nacos: subscribe %s err, use the cached instances and retry: %v", serviceName, err)
+		go w.resubscribe(failed)
+	default:
+		_ = w.Stop()
+		return nil, err
+	}
+	w.notify()
+	return w, nil
+}
+
+// subscribe subscribes targets[i] of idx, and returns the failed ones.
+func (w *watcher) subscribe(idx []int) ([]int, error) {
+	w.subMu.Lock()
+	defer w.subMu.Unlock()
+	var (
+		failed []int
+		errs   []error
+	)
+	for _, i := range idx {
+		if w.ctx.Err() != nil {
+			// stopped
+			return nil, nil
+		}
+		if err := w.cli.Subscribe(w.subscribeParams[i]); err != nil {
+			failed = append(failed, i)
+			errs = append(errs, fmt.Errorf("subscribe %s: %w", w.targets[i].service, err))
+			continue
+		}
+		w.subscribed[i] = true
+	}
+	return failed, errors.Join(errs...)
+}
+
+// resubscribe retries the failed subscriptions until they succeed or the watcher stops.
+func (w *watcher) resubscribe(failed []int) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+	for len(failed) > 0 {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		var err error
+		if failed, err = w.subscribe(failed); err != nil {
+			w.log.Warnf("nacos: subscribe %s err, retry later: %v", w.serviceName, err)
+		}
+	}
+}
+
+// cached reports whether the disk cache has instances of the service.
+func (w *watcher) cached() bool {
+	if w.cacheDir == "" {
+		return false
+	}
+	items, err := loadCache(w.cacheDir, w.groupName, w.serviceName)
+	return err == nil && len(items) > 0
+}
+
+func (w *watcher) notify() {
 	select {
 	case w.watchChan <- struct{}{}:
 	default:
 	}
-	return w, e
 }
 
+// onChange is the subscribe callback of targets[i], the pushed instances replace the snapshot.
+// Errors keep the last snapshot.
+func (w *watcher) onChange(i int, services []model.Instance, err error) {
+	if err != nil {
+		w.log.Warnf("nacos: watch service %s err, keep the last instances: %v", w.targets[i].service, err)
+		return
+	}
+	w.mu.Lock()
+	w.hosts[i], w.known[i] = services, true
+	w.mu.Unlock()
+	if w.update() {
+		w.notify()
+	}
+}
+
+// update aggregates the snapshot once all targets are known, persists it and reports the delta.
+func (w *watcher) update() bool {
+	w.mu.Lock()
+	for _, ok := range w.known {
+		if !ok {
+			w.mu.Unlock()
+			return false
+		}
+	}
+	old := w.instances
+	items := aggregate(w.name, w.targets, w.hosts, w.healthyOnly)
+	w.instances = items
+	w.synced = true
+	w.mu.Unlock()
+
+	if w.cacheDir != "" {
+		if err := saveCache(w.cacheDir, w.groupName, w.serviceName, items); err != nil {
+			w.log.Warnf("nacos: save instances cache of %s err: %v", w.serviceName, err)
+		}
+	}
+	if w.deltaHandler != nil {
+		if d := diff(w.serviceName, old, items); !d.Empty() {
+			w.deltaHandler(d)
+		}
+	}
+	return true
+}
+
+// Next returns the latest instances.
+// Instances pushed by nacos are returned without querying nacos again. Before the first push
+// the instances are queried, falling back to the disk cache when nacos is unreachable.
 func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
 	select {
 	case <-w.ctx.Done():
 		return nil, w.ctx.Err()
 	case <-w.watchChan:
 	}
-	res, err := w.cli.GetService(vo.GetServiceParam{
-		ServiceName: w.serviceName,
-		GroupName:   w.groupName,
-		Clusters:    w.clusters,
-	})
-	if err != nil {
-		return nil, err
+
+	w.mu.Lock()
+	synced := w.synced
+	w.mu.Unlock()
+	if !synced {
+		if err := w.query(); err != nil {
+			return w.fallback(err)
+		}
+		w.update()
 	}
-	items := make([]*registry.ServiceInstance, 0, len(res.Hosts))
-	for _, in := range res.Hosts {
-		kind := w.kind
-		if k, ok := in.Metadata["kind"]; ok {
-			kind = k
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]*registry.ServiceInstance(nil), w.instances...), nil
+}
+
+// query queries the targets not pushed yet, pushed instances are newer than the query result.
+func (w *watcher) query() error {
+	for i, t := range w.targets {
+		w.mu.Lock()
+		known := w.known[i]
+		w.mu.Unlock()
+		if known {
+			continue
 		}
-		items = append(items, &registry.ServiceInstance{
-			ID:        in.InstanceId,
-			Name:      res.Name,
-			Version:   in.Metadata["version"],
-			Metadata:  in.Metadata,
-			Endpoints: []string{fmt.Sprintf("%s://%s:%d", kind, in.Ip, in.Port)},
+		res, err := w.cli.GetService(vo.GetServiceParam{
+			ServiceName: t.service,
+			GroupName:   w.groupName,
+			Clusters:    w.clusters,
 		})
+		if err != nil {
+			return err
+		}
+		w.mu.Lock()
+		if !w.known[i] {
+			w.hosts[i], w.known[i] = res.Hosts, true
+		}
+		w.mu.Unlock()
 	}
-	return items, nil
+	return nil
+}
+
+// fallback returns the disk cache when nacos is unreachable before the first push.
+func (w *watcher) fallback(err error) ([]*registry.ServiceInstance, error) {
+	if w.cacheDir == "" {
+		// query again on the next call
+		w.notify()
+		return nil, err
+	}
+	cached, e := loadCache(w.cacheDir, w.groupName, w.serviceName)
+	if e != nil {
+		w.log.Warnf("nacos: load instances cache of %s err: %v", w.serviceName, e)
+		w.notify()
+		return nil, err
+	}
+	w.log.Warnf("nacos: get service %s err, use %d cached instances: %v", w.serviceName, len(cached), err)
+	// query again later, the cache is used until then
+	time.AfterFunc(retryInterval, w.notify)
+	w.mu.Lock()
+	if !w.synced {
+		w.instances = cached
+	}
+	w.mu.Unlock()
+	return cached, nil
 }
 
 func (w *watcher) Stop() error {
-	err := w.cli.Unsubscribe(w.subscribeParam)
 	w.cancel()
-	return err
+	w.subMu.Lock()
+	defer w.subMu.Unlock()
+	var errs []error
+	for i, param := range w.subscribeParams {
+		if w.subscribed[i] {
+			errs = append(errs, w.cli.Unsubscribe(param))
+			w.subscribed[i] = false
+		}
+	}
+	return errors.Join(errs...)
 }
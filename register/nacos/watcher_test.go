@@ -0,0 +1,398 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+func TestRegistry_Metadata(t *testing.T) {
+	cli := newFakeNamingClient()
+	r := New(cli, WithCluster("sh"), WithZone("sh-a"), WithWeight(12.5))
+	si := &registry.ServiceInstance{
+		ID:        "1",
+		Name:      "md",
+		Version:   "v1.0.0",
+		Metadata:  map[string]string{"idc": "shanghai"},
+		Endpoints: []string{"grpc://127.0.0.1:9000"},
+	}
+	if err := r.Register(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := si.Metadata["zone"]; ok {
+		t.Errorf("Register should not modify the metadata of the instance")
+	}
+	want := map[string]string{
+		"idc":     "shanghai",
+		"kind":    "grpc",
+		"version": "v1.0.0",
+		"weight":  "12.5",
+		"cluster": "sh",
+		"zone":    "sh-a",
+		"healthy": "true",
+	}
+	check := func(items []*registry.ServiceInstance) {
+		t.Helper()
+		if len(items) != 1 {
+			t.Fatalf("expect 1 instance, got %d", len(items))
+		}
+		for k, v := range want {
+			if items[0].Metadata[k] != v {
+				t.Errorf("expect metadata %s=%s, got %v", k, v, items[0].Metadata)
+			}
+		}
+		if items[0].Version != "v1.0.0" || items[0].Endpoints[0] != "grpc://127.0.0.1:9000" {
+			t.Errorf("unexpected instance %+v", items[0])
+		}
+	}
+
+	items, err := r.GetService(context.Background(), "md.grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(items)
+
+	w, err := r.Watch(context.Background(), "md.grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Stop() }()
+	items, err = w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(items)
+}
+
+func TestWatcher_Callback(t *testing.T) {
+	cli := newFakeNamingClient()
+	r := New(cli)
+	w, err := r.Watch(context.Background(), "cb.grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 || cli.queryCount() != 1 {
+		t.Fatalf("expect 0 instances after 1 query, got %d after %d", len(items), cli.queryCount())
+	}
+
+	si := &registry.ServiceInstance{ID: "1", Name: "cb", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	if err = r.Register(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	if items, err = w.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || cli.queryCount() != 1 {
+		t.Fatalf("expect 1 instance from the callback without query, got %d after %d queries", len(items), cli.queryCount())
+	}
+
+	// transient errors keep the snapshot and are not returned
+	cli.pushError("DEFAULT_GROUP", "cb.grpc", errFakeUnavailable)
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.Next()
+		done <- err
+	}()
+	select {
+	case err = <-done:
+		t.Fatalf("Next should block after a push error, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err = <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expect context.Canceled after Stop, got %v", err)
+	}
+}
+
+func TestWatcher_ColdStartCache(t *testing.T) {
+	dir := t.TempDir()
+	cli := newFakeNamingClient()
+	r := New(cli, WithCacheDir(dir))
+	si := &registry.ServiceInstance{ID: "1", Name: "cold", Version: "v1", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	if err := r.Register(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Watch(context.Background(), "cold.grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Next(); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Stop()
+
+	// nacos is unreachable when the app restarts
+	down := newFakeNamingClient()
+	down.setUnavailable(true)
+	if w, err = New(down).Watch(context.Background(), "cold.grpc"); !errors.Is(err, errFakeUnavailable) || w != nil {
+		t.Errorf("expect errFakeUnavailable and no watcher without cache, got %v", err)
+	}
+
+	defer func(d time.Duration) { retryInterval = d }(retryInterval)
+	retryInterval = 10 * time.Millisecond
+	w, err = New(down, WithCacheDir(dir)).Watch(context.Background(), "cold.grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Stop() }()
+	items, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Version != "v1" || items[0].Endpoints[0] != "grpc://127.0.0.1:9000" {
+		t.Errorf("unexpected cached instances %+v", items)
+	}
+
+	// the subscription is retried once nacos is back
+	down.setUnavailable(false)
+	deadline := time.Now().Add(time.Second)
+	for down.subscriberCount("DEFAULT_GROUP", "cold.grpc") != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expect the subscription retried")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err = w.Stop(); err != nil || down.subscriberCount("DEFAULT_GROUP", "cold.grpc") != 0 {
+		t.Errorf("expect unsubscribed after Stop, got %v", err)
+	}
+}
+
+func TestWatcher_Delta(t *testing.T) {
+	cli := newFakeNamingClient()
+	var (
+		mu     sync.Mutex
+		deltas []Delta
+	)
+	r := New(cli, WithDeltaHandler(func(d Delta) {
+		mu.Lock()
+		deltas = append(deltas, d)
+		mu.Unlock()
+	}))
+	w, err := r.Watch(context.Background(), "delta.grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Stop() }()
+
+	si := &registry.ServiceInstance{ID: "1", Name: "delta", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	if err = r.Register(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	si.Metadata = map[string]string{"weight": "50"}
+	if err = r.Update(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Deregister(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(deltas) != 3 {
+		t.Fatalf("expect 3 deltas, got %+v", deltas)
+	}
+	if len(deltas[0].Added) != 1 || len(deltas[1].Updated) != 1 || len(deltas[2].Removed) != 1 {
+		t.Errorf("unexpected deltas %+v", deltas)
+	}
+	if deltas[1].Updated[0].Metadata["weight"] != "50" || deltas[1].Service != "delta.grpc" {
+		t.Errorf("unexpected updated instance %+v", deltas[1])
+	}
+}
+
+func TestRegistry_MultiEndpoint(t *testing.T) {
+	cli := newFakeNamingClient()
+	r := New(cli, WithPrefix("/microservices"))
+	a := &registry.ServiceInstance{
+		ID:        "a",
+		Name:      "multi",
+		Version:   "v1",
+		Endpoints: []string{"grpc://127.0.0.1:9000", "http://127.0.0.1:8000"},
+	}
+	b := &registry.ServiceInstance{
+		ID:        "b",
+		Name:      "multi",
+		Version:   "v1",
+		Endpoints: []string{"http://127.0.0.2:8000"},
+	}
+	for _, si := range []*registry.ServiceInstance{a, b} {
+		if err := r.Register(context.Background(), si); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(cli.instances("DEFAULT_GROUP", "microservices.multi.grpc")); n != 1 {
+		t.Fatalf("expect 1 instance of microservices.multi.grpc, got %d", n)
+	}
+	// registered by another framework without scheme suffix
+	if _, err := cli.RegisterInstance(vo.RegisterInstanceParam{
+		Ip: "127.0.0.3", Port: 9000, ServiceName: "microservices.multi", Weight: 1, Enable: true, Healthy: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(items []*registry.ServiceInstance) {
+		t.Helper()
+		if len(items) != 3 {
+			t.Fatalf("expect 3 instances, got %+v", items)
+		}
+		got := make(map[string][]string)
+		for _, si := range items {
+			if si.Name != "multi" {
+				t.Errorf("expect name multi, got %s", si.Name)
+			}
+			if _, ok := si.Metadata[metadataID]; ok {
+				t.Errorf("metadata %s should be removed", metadataID)
+			}
+			got[si.ID] = si.Endpoints
+		}
+		if !slices.Equal(got["a"], a.Endpoints) || !slices.Equal(got["b"], b.Endpoints) {
+			t.Errorf("unexpected endpoints %v", got)
+		}
+		if eps := got["127.0.0.3#9000##DEFAULT_GROUP@@microservices.multi"]; !slices.Equal(eps, []string{"grpc://127.0.0.3:9000"}) {
+			t.Errorf("unexpected endpoints of legacy instance %v", got)
+		}
+	}
+
+	items, err := r.GetService(context.Background(), "multi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(items)
+
+	w, err := r.Watch(context.Background(), "multi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Stop() }()
+	if items, err = w.Next(); err != nil {
+		t.Fatal(err)
+	}
+	check(items)
+
+	// a single scheme is watched when the name ends with the scheme
+	if items, err = r.GetService(context.Background(), "multi.http"); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Endpoints[0] != "http://127.0.0.1:8000" || len(items[0].Endpoints) != 1 {
+		t.Errorf("unexpected http instances %+v", items)
+	}
+
+	if err = r.Deregister(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	if items, err = w.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expect 2 instances after deregister, got %+v", items)
+	}
+
+	if _, err = r.GetService(context.Background(), "missing"); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("expect ErrServiceNotFound, got %v", err)
+	}
+}
+
+func TestWatcher_PartialSubscribe(t *testing.T) {
+	defer func(d time.Duration) { retryInterval = d }(retryInterval)
+	retryInterval = 10 * time.Millisecond
+	cli := newFakeNamingClient()
+	r := New(cli)
+	a := &registry.ServiceInstance{ID: "a", Name: "part", Endpoints: []string{"grpc://127.0.0.1:9000", "http://127.0.0.1:8000"}}
+	if err := r.Register(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+
+	cli.setSubscribeErr("part.http", errFakeUnavailable)
+	w, err := r.Watch(context.Background(), "part")
+	if err != nil {
+		t.Fatalf("expect the other schemes watched, got %v", err)
+	}
+	defer func() { _ = w.Stop() }()
+	items, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || !slices.Equal(items[0].Endpoints, []string{"grpc://127.0.0.1:9000"}) {
+		t.Errorf("expect the grpc endpoint only, got %+v", items)
+	}
+
+	// the failed scheme is subscribed again
+	cli.setSubscribeErr("part.http", nil)
+	deadline := time.Now().Add(time.Second)
+	for cli.subscriberCount("DEFAULT_GROUP", "part.http") != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expect the subscription retried")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	b := &registry.ServiceInstance{ID: "b", Name: "part", Endpoints: []string{"http://127.0.0.2:8000"}}
+	if err = r.Register(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+	if items, err = w.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expect the http instances after resubscribing, got %+v", items)
+	}
+
+	// all schemes failing is an error without cache
+	cli.setSubscribeErr("part.grpc", errFakeUnavailable)
+	cli.setSubscribeErr("part.http", errFakeUnavailable)
+	cli.setSubscribeErr("part", errFakeUnavailable)
+	if _, err = r.Watch(context.Background(), "part"); !errors.Is(err, errFakeUnavailable) {
+		t.Errorf("expect errFakeUnavailable, got %v", err)
+	}
+}
+
+func TestWatcher_Unhealthy(t *testing.T) {
+	cli := newFakeNamingClient()
+	for _, in := range []vo.RegisterInstanceParam{
+		{Ip: "127.0.0.1", Port: 9000, Weight: 1, Enable: true, Healthy: true},
+		{Ip: "127.0.0.2", Port: 9000, Weight: 1, Enable: true, Healthy: false},
+		{Ip: "127.0.0.3", Port: 9000, Weight: 1, Enable: false, Healthy: true},
+	} {
+		in.ServiceName = "sick.grpc"
+		if _, err := cli.RegisterInstance(in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tt := range []struct {
+		keep bool
+		want int
+	}{
+		{false, 1},
+		{true, 3},
+	} {
+		r := New(cli, WithUnhealthyInstances(tt.keep))
+		items, err := r.GetService(context.Background(), "sick.grpc")
+		if err != nil || len(items) != tt.want {
+			t.Errorf("keep %v: expect %d instances from GetService, got %+v, %v", tt.keep, tt.want, items, err)
+		}
+		w, err := r.Watch(context.Background(), "sick.grpc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if items, err = w.Next(); err != nil || len(items) != tt.want {
+			t.Errorf("keep %v: expect %d instances from Watch, got %+v, %v", tt.keep, tt.want, items, err)
+		}
+		for _, si := range items {
+			if healthy := si.Endpoints[0] == "grpc://127.0.0.1:9000"; si.Metadata["healthy"] != strconv.FormatBool(healthy) {
+				t.Errorf("unexpected healthy metadata of %+v", si)
+			}
+		}
+		_ = w.Stop()
+	}
+}
@@ -0,0 +1,194 @@
+package polaris
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+var (
+	_ api.ConsumerAPI = (*fakePolaris)(nil)
+	_ api.ProviderAPI = (*fakePolaris)(nil)
+	_ api.LimitAPI    = (*fakeLimiter)(nil)
+)
+
+// fakeInstance is a polaris instance, methods not overridden panic.
+type fakeInstance struct {
+	model.Instance
+
+	id, service, host string
+	port              int
+	weight            int
+	healthy           bool
+	zone              string
+	metadata          map[string]string
+}
+
+func (i *fakeInstance) GetId() string                  { return i.id }
+func (i *fakeInstance) GetService() string             { return i.service }
+func (i *fakeInstance) GetHost() string                { return i.host }
+func (i *fakeInstance) GetPort() uint32                { return uint32(i.port) }
+func (i *fakeInstance) GetWeight() int                 { return i.weight }
+func (i *fakeInstance) IsHealthy() bool                { return i.healthy }
+func (i *fakeInstance) IsIsolated() bool               { return false }
+func (i *fakeInstance) GetLogicSet() string            { return "" }
+func (i *fakeInstance) GetZone() string                { return i.zone }
+func (i *fakeInstance) GetMetadata() map[string]string { return i.metadata }
+func (i *fakeInstance) GetVersion() string             { return i.metadata["version"] }
+func (i *fakeInstance) GetInstanceKey() model.InstanceKey {
+	return model.InstanceKey{Host: i.host, Port: i.port}
+}
+
+// fakePolaris is an in-memory polaris server, serving as both ConsumerAPI and ProviderAPI.
+type fakePolaris struct {
+	api.ConsumerAPI
+	api.ProviderAPI
+
+	mu        sync.Mutex
+	seq       int
+	instances map[string][]model.Instance // service -> instances
+	// routes are the addresses GetInstances returns, all instances when absent
+	routes    map[string][]string
+	routeReqs []*api.GetInstancesRequest
+	results   []*api.ServiceCallResult
+	events    chan model.SubScribeEvent
+}
+
+func newFakePolaris() *fakePolaris {
+	return &fakePolaris{
+		instances: make(map[string][]model.Instance),
+		routes:    make(map[string][]string),
+		events:    make(chan model.SubScribeEvent, 16),
+	}
+}
+
+func (p *fakePolaris) SDKContext() api.SDKContext { return nil }
+
+func (p *fakePolaris) Destroy() {}
+
+func (p *fakePolaris) RegisterInstance(req *api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seq++
+	ins := &fakeInstance{
+		id:       fmt.Sprintf("ins%d", p.seq),
+		service:  req.Service,
+		host:     req.Host,
+		port:     req.Port,
+		weight:   *req.Weight,
+		healthy:  *req.Healthy,
+		metadata: req.Metadata,
+	}
+	if req.Location != nil {
+		ins.zone = req.Location.Zone
+	}
+	p.instances[req.Service] = append(p.instances[req.Service], ins)
+	return &model.InstanceRegisterResponse{InstanceID: ins.id}, nil
+}
+
+func (p *fakePolaris) Deregister(req *api.InstanceDeRegisterRequest) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	list := p.instances[req.Service]
+	for i, ins := range list {
+		if ins.GetId() == req.InstanceID {
+			p.instances[req.Service] = append(list[:i], list[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("fake polaris: instance %s not found", req.InstanceID)
+}
+
+func (p *fakePolaris) add(ins *fakeInstance) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.instances[ins.service] = append(p.instances[ins.service], ins)
+}
+
+func (p *fakePolaris) GetAllInstances(req *api.GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &model.InstancesResponse{Instances: append([]model.Instance(nil), p.instances[req.Service]...)}, nil
+}
+
+// GetInstances returns the instances whose addresses are in routes, simulating route rules.
+func (p *fakePolaris) GetInstances(req *api.GetInstancesRequest) (*model.InstancesResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.routeReqs = append(p.routeReqs, req)
+	routes, ok := p.routes[req.Service]
+	if !ok {
+		return &model.InstancesResponse{Instances: append([]model.Instance(nil), p.instances[req.Service]...)}, nil
+	}
+	var res []model.Instance
+	for _, ins := range p.instances[req.Service] {
+		for _, addr := range routes {
+			if instanceAddress(ins) == addr {
+				res = append(res, ins)
+			}
+		}
+	}
+	return &model.InstancesResponse{Instances: res}, nil
+}
+
+func (p *fakePolaris) GetRouteRule(req *api.GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+	return &model.ServiceRuleResponse{Type: model.EventRouting, Service: model.ServiceKey{Namespace: req.Namespace, Service: req.Service}}, nil
+}
+
+func (p *fakePolaris) UpdateServiceCallResult(req *api.ServiceCallResult) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.results = append(p.results, req)
+	return nil
+}
+
+func (p *fakePolaris) WatchService(req *api.WatchServiceRequest) (*model.WatchServiceResponse, error) {
+	all, _ := p.GetAllInstances(&api.GetAllInstancesRequest{GetAllInstancesRequest: model.GetAllInstancesRequest{Service: req.Key.Service}})
+	return &model.WatchServiceResponse{EventChannel: p.events, GetAllInstancesResp: all}, nil
+}
+
+func (p *fakePolaris) lastResult() (*api.ServiceCallResult, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.results) == 0 {
+		return nil, 0
+	}
+	return p.results[len(p.results)-1], len(p.results)
+}
+
+// fakeLimiter allows limit requests and records the quota requests.
+type fakeLimiter struct {
+	api.LimitAPI
+
+	mu       sync.Mutex
+	limit    int
+	requests []*model.QuotaRequestImpl
+	released int
+}
+
+func (l *fakeLimiter) GetQuota(req api.QuotaRequest) (api.QuotaFuture, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests = append(l.requests, req.(*model.QuotaRequestImpl))
+	code := model.QuotaResultOk
+	if len(l.requests) > l.limit {
+		code = model.QuotaResultLimited
+	}
+	return &fakeFuture{limiter: l, resp: &model.QuotaResponse{Code: code}}, nil
+}
+
+type fakeFuture struct {
+	api.QuotaFuture
+	limiter *fakeLimiter
+	resp    *model.QuotaResponse
+}
+
+func (f *fakeFuture) Get() *model.QuotaResponse { return f.resp }
+
+func (f *fakeFuture) Release() {
+	f.limiter.mu.Lock()
+	defer f.limiter.mu.Unlock()
+	f.limiter.released++
+}
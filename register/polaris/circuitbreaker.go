@@ -0,0 +1,49 @@
+package polaris
+
+import (
+	"context"
+	"time"
+
+	"github.com/jiushengTech/kratos/v2/errors"
+	"github.com/jiushengTech/kratos/v2/middleware"
+	"github.com/jiushengTech/kratos/v2/selector"
+	"github.com/jiushengTech/kratos/v2/transport"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// CircuitBreaker is a client middleware reporting call results of the selected instance to polaris.
+// Polaris breaks the instances according to the circuit breaker rules, and the broken
+// instances are filtered out by NodeFilter.
+func (r *Registry) CircuitBreaker() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			start := time.Now()
+			reply, err := handler(ctx, req)
+			p, ok := selector.FromPeerContext(ctx)
+			if !ok || p.Node == nil {
+				return reply, err
+			}
+			ins, e := r.instance(p.Node.ServiceName(), p.Node.Address())
+			if e != nil {
+				return reply, err
+			}
+
+			result := &api.ServiceCallResult{}
+			result.SetCalledInstance(ins)
+			result.SetDelay(time.Since(start))
+			if tr, ok := transport.FromClientContext(ctx); ok {
+				result.Method = tr.Operation()
+			}
+			// only server errors break the instance, the same as kratos circuitbreaker
+			if err != nil && (errors.IsInternalServer(err) || errors.IsServiceUnavailable(err) || errors.IsGatewayTimeout(err)) {
+				result.SetRetStatus(model.RetFail)
+			} else {
+				result.SetRetStatus(model.RetSuccess)
+			}
+			result.SetRetCode(int32(errors.Code(err)))
+			_ = r.consumer.UpdateServiceCallResult(result)
+			return reply, err
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+
+	kerrors "github.com/jiushengTech/kratos/v2/errors"
+	"github.com/jiushengTech/kratos/v2/middleware/ratelimit"
+	"github.com/jiushengTech/kratos/v2/registry"
+	"github.com/jiushengTech/kratos/v2/selector"
+	"github.com/jiushengTech/kratos/v2/transport"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// testTransport is a transport with the operation and request header.
+type testTransport struct {
+	operation string
+	header    headerCarrier
+}
+
+type headerCarrier http.Header
+
+func (h headerCarrier) Get(key string) string      { return http.Header(h).Get(key) }
+func (h headerCarrier) Set(key, value string)      { http.Header(h).Set(key, value) }
+func (h headerCarrier) Add(key, value string)      { http.Header(h).Add(key, value) }
+func (h headerCarrier) Values(key string) []string { return http.Header(h).Values(key) }
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (tr *testTransport) Kind() transport.Kind            { return transport.KindGRPC }
+func (tr *testTransport) Endpoint() string                { return "" }
+func (tr *testTransport) Operation() string               { return tr.operation }
+func (tr *testTransport) RequestHeader() transport.Header { return tr.header }
+func (tr *testTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+func (tr *testTransport) String() string                  { return tr.operation }
+
+func newTestTransport(operation string, kv ...string) *testTransport {
+	tr := &testTransport{operation: operation, header: headerCarrier{}}
+	for i := 0; i+1 < len(kv); i += 2 {
+		tr.header.Set(kv[i], kv[i+1])
+	}
+	return tr
+}
+
+func testNodes(pol *fakePolaris, service string, addrs ...string) []selector.Node {
+	nodes := make([]selector.Node, 0, len(addrs))
+	for _, addr := range addrs {
+		host, port, _ := net.SplitHostPort(addr)
+		p, _ := strconv.Atoi(port)
+		ins := &fakeInstance{id: addr, service: service, host: host, port: p, weight: 100, healthy: true}
+		pol.add(ins)
+		nodes = append(nodes, selector.NewNode("grpc", instanceAddress(ins), &registry.ServiceInstance{Name: service}))
+	}
+	return nodes
+}
+
+func TestRegistry_NodeFilter(t *testing.T) {
+	p := newFakePolaris()
+	r := NewRegistry(p, p, WithNamespace("prod"))
+	nodes := testNodes(p, "svc", "127.0.0.1:9000", "127.0.0.2:9001")
+	p.routes["svc"] = []string{"127.0.0.2:9001"}
+
+	filter := r.NodeFilter(
+		WithRouterService("caller"),
+		WithRouterMetadata(map[string]string{"env": "gray"}),
+		WithRouterHeaders("x-uid"),
+	)
+	ctx := transport.NewClientContext(context.Background(), newTestTransport("/svc/Hello", "x-uid", "42"))
+	got := filter(ctx, nodes)
+	if len(got) != 1 || got[0].Address() != "127.0.0.2:9001" {
+		t.Fatalf("expect routed node 127.0.0.2:9001, got %v", got)
+	}
+
+	req := p.routeReqs[0]
+	if req.Namespace != "prod" || req.SourceService.Service != "caller" || req.SourceService.Metadata["env"] != "gray" {
+		t.Errorf("unexpected route request %+v", req.GetInstancesRequest)
+	}
+	if len(req.Arguments) != 1 || req.Arguments[0] != model.BuildHeaderArgument("x-uid", "42") {
+		t.Errorf("unexpected route arguments %v", req.Arguments)
+	}
+
+	rule, err := r.GetRouteRule("svc")
+	if err != nil || rule.Service.Service != "svc" || rule.Service.Namespace != "prod" {
+		t.Errorf("unexpected route rule %+v, %v", rule, err)
+	}
+}
+
+func TestRegistry_CircuitBreaker(t *testing.T) {
+	p := newFakePolaris()
+	r := NewRegistry(p, p)
+	nodes := testNodes(p, "svc", "127.0.0.1:9000")
+
+	tests := []struct {
+		name   string
+		err    error
+		status model.RetStatus
+		code   int32
+	}{
+		{name: "success", status: model.RetSuccess, code: 200},
+		{name: "clientError", err: kerrors.BadRequest("BAD", ""), status: model.RetSuccess, code: 400},
+		{name: "serverError", err: kerrors.ServiceUnavailable("DOWN", ""), status: model.RetFail, code: 503},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := r.CircuitBreaker()(func(ctx context.Context, req interface{}) (interface{}, error) {
+				// the selector fills the peer
+				p, _ := selector.FromPeerContext(ctx)
+				p.Node = nodes[0]
+				return "reply", tt.err
+			})
+			ctx := transport.NewClientContext(context.Background(), newTestTransport("/svc/Hello"))
+			ctx = selector.NewPeerContext(ctx, &selector.Peer{})
+			reply, err := h(ctx, "req")
+			if reply != "reply" || !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected reply %v, %v", reply, err)
+			}
+			res, n := p.lastResult()
+			if n != i+1 {
+				t.Fatalf("expect %d results, got %d", i+1, n)
+			}
+			if res.GetRetStatus() != tt.status || res.GetRetCodeValue() != tt.code || res.Method != "/svc/Hello" {
+				t.Errorf("unexpected result status=%v code=%v method=%s", res.GetRetStatus(), res.GetRetCodeValue(), res.Method)
+			}
+			if res.GetCalledInstance().GetId() != "127.0.0.1:9000" {
+				t.Errorf("unexpected called instance %s", res.GetCalledInstance().GetId())
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := &fakeLimiter{limit: 1}
+	m := RateLimit(limiter, WithLimitNamespace("prod"), WithLimitService("svc"), WithLimitHeaders("x-uid"))
+	h := m(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "reply", nil
+	})
+	ctx := transport.NewServerContext(context.Background(), newTestTransport("/svc/Hello", "x-uid", "42"))
+
+	if reply, err := h(ctx, "req"); err != nil || reply != "reply" {
+		t.Fatalf("expect the first request passes, got %v, %v", reply, err)
+	}
+	if _, err := h(ctx, "req"); !errors.Is(err, ratelimit.ErrLimitExceed) {
+		t.Fatalf("expect ErrLimitExceed, got %v", err)
+	}
+
+	req := limiter.requests[0]
+	if req.GetNamespace() != "prod" || req.GetService() != "svc" || req.GetMethod() != "/svc/Hello" {
+		t.Errorf("unexpected quota request %s/%s/%s", req.GetNamespace(), req.GetService(), req.GetMethod())
+	}
+	if args := req.Arguments(); len(args) != 1 || args[0] != model.BuildHeaderArgument("x-uid", "42") {
+		t.Errorf("unexpected quota arguments %v", args)
+	}
+	if limiter.released != 1 {
+		t.Errorf("expect the quota of the passed request released, got %d", limiter.released)
+	}
+}
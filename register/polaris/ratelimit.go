@@ -0,0 +1,80 @@
+package polaris
+
+import (
+	"context"
+	"time"
+
+	"github.com/jiushengTech/kratos/v2/middleware"
+	"github.com/jiushengTech/kratos/v2/middleware/ratelimit"
+	"github.com/jiushengTech/kratos/v2/transport"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type limitOptions struct {
+	namespace string
+	service   string
+	// request headers passed as rate limit arguments
+	headers []string
+	timeout time.Duration
+}
+
+// LimitOption is rate limit option.
+type LimitOption func(o *limitOptions)
+
+// WithLimitNamespace with namespace option.
+func WithLimitNamespace(namespace string) LimitOption {
+	return func(o *limitOptions) { o.namespace = namespace }
+}
+
+// WithLimitService with service option, the service the rate limit rules belong to.
+func WithLimitService(service string) LimitOption {
+	return func(o *limitOptions) { o.service = service }
+}
+
+// WithLimitHeaders with header keys option, the request headers are passed as rate limit arguments.
+func WithLimitHeaders(keys ...string) LimitOption {
+	return func(o *limitOptions) { o.headers = keys }
+}
+
+// WithLimitTimeout with timeout option of acquiring quota.
+func WithLimitTimeout(timeout time.Duration) LimitOption {
+	return func(o *limitOptions) { o.timeout = timeout }
+}
+
+// RateLimit is a server middleware applying the polaris rate limit rules of the service.
+// The operation of the request is the method of the rules, limited requests fail with ratelimit.ErrLimitExceed.
+func RateLimit(limiter api.LimitAPI, opts ...LimitOption) middleware.Middleware {
+	o := limitOptions{namespace: "default"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			quota := api.NewQuotaRequest()
+			quota.SetNamespace(o.namespace)
+			quota.SetService(o.service)
+			if o.timeout > 0 {
+				quota.SetTimeout(o.timeout)
+			}
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				quota.SetMethod(tr.Operation())
+				for _, k := range o.headers {
+					if v := tr.RequestHeader().Get(k); v != "" {
+						quota.AddArgument(model.BuildHeaderArgument(k, v))
+					}
+				}
+			}
+			future, err := limiter.GetQuota(quota)
+			if err != nil {
+				// let the request pass when polaris is unavailable
+				return handler(ctx, req)
+			}
+			if resp := future.Get(); resp != nil && resp.Code == api.QuotaResultLimited {
+				return nil, ratelimit.ErrLimitExceed
+			}
+			defer future.Release()
+			return handler(ctx, req)
+		}
+	}
+}
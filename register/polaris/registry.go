@@ -9,7 +9,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/jiushengTech/common/register"
+	"github.com/jiushengTech/kratos/v2/registry"
 
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
@@ -141,7 +142,7 @@ func NewRegistry(provider api.ProviderAPI, consumer api.ConsumerAPI, opts ...Opt
 		Namespace:    "default",
 		ServiceToken: "",
 		Protocol:     nil,
-		Weight:       0,
+		Weight:       100,
 		Priority:     0,
 		Healthy:      true,
 		Heartbeat:    true,
@@ -413,7 +414,19 @@ func instancesToServiceInstances(instances []model.Instance) []*registry.Service
 }
 
 func instanceToServiceInstance(instance model.Instance) *registry.ServiceInstance {
-	metadata := instance.GetMetadata()
+	metadata := make(map[string]string, len(instance.GetMetadata())+4)
+	for k, v := range instance.GetMetadata() {
+		metadata[k] = v
+	}
+	// polaris has no cluster, the logic set is the closest concept
+	if _, ok := metadata[register.MetadataCluster]; !ok {
+		metadata[register.MetadataCluster] = instance.GetLogicSet()
+	}
+	metadata[register.MetadataWeight] = strconv.Itoa(instance.GetWeight())
+	metadata[register.MetadataHealthy] = strconv.FormatBool(instance.IsHealthy() && !instance.IsIsolated())
+	if zone := instance.GetZone(); zone != "" {
+		metadata[register.MetadataZone] = zone
+	}
 	// Usually, it won't fail in kratos if register correctly
 	kind := ""
 	if k, ok := metadata["kind"]; ok {
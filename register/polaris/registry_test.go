@@ -0,0 +1,94 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestRegistry(t *testing.T) {
+	p := newFakePolaris()
+	r := NewRegistry(p, p, WithLocation(model.Location{Zone: "sh-a"}))
+	si := &registry.ServiceInstance{
+		Name:      "helloworld",
+		Version:   "v1.0.0",
+		Metadata:  map[string]string{"cluster": "sh"},
+		Endpoints: []string{"grpc://127.0.0.1:9000"},
+	}
+	if err := r.Register(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	if si.ID != "ins1" {
+		t.Fatalf("expect instance id ins1, got %s", si.ID)
+	}
+
+	items, err := r.GetService(context.Background(), "helloworldgrpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expect 1 instance, got %d", len(items))
+	}
+	want := map[string]string{
+		"kind":    "grpc",
+		"version": "v1.0.0",
+		"weight":  "100",
+		"cluster": "sh",
+		"healthy": "true",
+		"zone":    "sh-a",
+	}
+	for k, v := range want {
+		if items[0].Metadata[k] != v {
+			t.Errorf("expect metadata %s=%s, got %v", k, v, items[0].Metadata)
+		}
+	}
+	if items[0].Endpoints[0] != "grpc://127.0.0.1:9000" || items[0].Version != "v1.0.0" {
+		t.Errorf("unexpected instance %+v", items[0])
+	}
+
+	if err = r.Deregister(context.Background(), si); err != nil {
+		t.Fatal(err)
+	}
+	if items, err = r.GetService(context.Background(), "helloworldgrpc"); err != nil || len(items) != 0 {
+		t.Errorf("expect no instance after Deregister, got %d, %v", len(items), err)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	p := newFakePolaris()
+	a := &fakeInstance{id: "a", service: "svc", host: "127.0.0.1", port: 9000, weight: 100, healthy: true, metadata: map[string]string{"kind": "grpc"}}
+	p.add(a)
+	r := NewRegistry(p, p)
+	w, err := r.Watch(context.Background(), "svc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Stop() }()
+
+	items, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ID != "a" {
+		t.Fatalf("unexpected first instances %+v", items)
+	}
+
+	b := &fakeInstance{id: "b", service: "svc", host: "127.0.0.2", port: 9000, weight: 50, healthy: true, metadata: map[string]string{"kind": "grpc"}}
+	p.events <- &model.InstanceEvent{AddEvent: &model.InstanceAddEvent{Instances: []model.Instance{b}}}
+	if items, err = w.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[1].Metadata["weight"] != "50" {
+		t.Fatalf("unexpected instances after add %+v", items)
+	}
+
+	p.events <- &model.InstanceEvent{DeleteEvent: &model.InstanceDeleteEvent{Instances: []model.Instance{a}}}
+	if items, err = w.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ID != "b" {
+		t.Fatalf("unexpected instances after delete %+v", items)
+	}
+}
@@ -0,0 +1,130 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/jiushengTech/kratos/v2/selector"
+	"github.com/jiushengTech/kratos/v2/transport"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type routerOptions struct {
+	// the caller service, matched by the source of route rules
+	service  string
+	metadata map[string]string
+	// request headers passed as route arguments
+	headers []string
+}
+
+// RouterOption is router option.
+type RouterOption func(o *routerOptions)
+
+// WithRouterService with caller service option, the source service of route rules.
+func WithRouterService(service string) RouterOption {
+	return func(o *routerOptions) { o.service = service }
+}
+
+// WithRouterMetadata with caller metadata option, matched by the source labels of route rules.
+func WithRouterMetadata(md map[string]string) RouterOption {
+	return func(o *routerOptions) { o.metadata = md }
+}
+
+// WithRouterHeaders with header keys option, the request headers are passed as route arguments.
+func WithRouterHeaders(keys ...string) RouterOption {
+	return func(o *routerOptions) { o.headers = keys }
+}
+
+// NodeFilter returns a selector filter applying the polaris route rules of the callee service.
+// Circuit broken and unhealthy instances are filtered out as well.
+//
+//	grpc.DialInsecure(ctx, grpc.WithNodeFilter(r.NodeFilter(polaris.WithRouterService("caller"))))
+func (r *Registry) NodeFilter(opts ...RouterOption) selector.NodeFilter {
+	var o routerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(ctx context.Context, nodes []selector.Node) []selector.Node {
+		if len(nodes) == 0 {
+			return nodes
+		}
+		req := &api.GetInstancesRequest{
+			GetInstancesRequest: model.GetInstancesRequest{
+				Service:    nodes[0].ServiceName(),
+				Namespace:  r.opt.Namespace,
+				Timeout:    &r.opt.Timeout,
+				RetryCount: &r.opt.RetryCount,
+			},
+		}
+		if o.service != "" || len(o.metadata) > 0 {
+			req.SourceService = &model.ServiceInfo{
+				Service:   o.service,
+				Namespace: r.opt.Namespace,
+				Metadata:  o.metadata,
+			}
+		}
+		if tr, ok := transport.FromClientContext(ctx); ok {
+			for _, k := range o.headers {
+				if v := tr.RequestHeader().Get(k); v != "" {
+					req.Arguments = append(req.Arguments, model.BuildHeaderArgument(k, v))
+				}
+			}
+		}
+		resp, err := r.consumer.GetInstances(req)
+		if err != nil {
+			// keep the nodes when polaris is unavailable
+			return nodes
+		}
+		routed := make(map[string]struct{}, len(resp.GetInstances()))
+		for _, ins := range resp.GetInstances() {
+			routed[instanceAddress(ins)] = struct{}{}
+		}
+		res := make([]selector.Node, 0, len(nodes))
+		for _, n := range nodes {
+			if _, ok := routed[n.Address()]; ok {
+				res = append(res, n)
+			}
+		}
+		return res
+	}
+}
+
+// GetRouteRule returns the route rule of the service.
+func (r *Registry) GetRouteRule(service string) (*model.ServiceRuleResponse, error) {
+	return r.consumer.GetRouteRule(&api.GetServiceRuleRequest{
+		GetServiceRuleRequest: model.GetServiceRuleRequest{
+			Service:    service,
+			Namespace:  r.opt.Namespace,
+			Timeout:    &r.opt.Timeout,
+			RetryCount: &r.opt.RetryCount,
+		},
+	})
+}
+
+// instance returns the instance of the service at addr.
+func (r *Registry) instance(service, addr string) (model.Instance, error) {
+	resp, err := r.consumer.GetAllInstances(&api.GetAllInstancesRequest{
+		GetAllInstancesRequest: model.GetAllInstancesRequest{
+			Service:    service,
+			Namespace:  r.opt.Namespace,
+			Timeout:    &r.opt.Timeout,
+			RetryCount: &r.opt.RetryCount,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ins := range resp.GetInstances() {
+		if instanceAddress(ins) == addr {
+			return ins, nil
+		}
+	}
+	return nil, fmt.Errorf("polaris: instance %s of %s not found", addr, service)
+}
+
+func instanceAddress(ins model.Instance) string {
+	return net.JoinHostPort(ins.GetHost(), strconv.Itoa(int(ins.GetPort())))
+}
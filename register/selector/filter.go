@@ -0,0 +1,69 @@
+package selector
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jiushengTech/common/register"
+	"github.com/jiushengTech/kratos/v2/selector"
+)
+
+// Version is version filter, nodes with any of the versions are kept.
+func Version(versions ...string) selector.NodeFilter {
+	return func(_ context.Context, nodes []selector.Node) []selector.Node {
+		res := make([]selector.Node, 0, len(nodes))
+		for _, n := range nodes {
+			for _, v := range versions {
+				if n.Version() == v {
+					res = append(res, n)
+					break
+				}
+			}
+		}
+		return res
+	}
+}
+
+// Metadata is metadata filter, nodes having all the key value pairs are kept.
+func Metadata(md map[string]string) selector.NodeFilter {
+	return func(_ context.Context, nodes []selector.Node) []selector.Node {
+		res := make([]selector.Node, 0, len(nodes))
+	next:
+		for _, n := range nodes {
+			for k, v := range md {
+				if n.Metadata()[k] != v {
+					continue next
+				}
+			}
+			res = append(res, n)
+		}
+		return res
+	}
+}
+
+// Tag is tag filter, nodes having all the tags in metadata register.MetadataTags are kept.
+func Tag(tags ...string) selector.NodeFilter {
+	return func(_ context.Context, nodes []selector.Node) []selector.Node {
+		res := make([]selector.Node, 0, len(nodes))
+	next:
+		for _, n := range nodes {
+			has := strings.Split(n.Metadata()[register.MetadataTags], ",")
+			for _, t := range tags {
+				if !containsTag(has, t) {
+					continue next
+				}
+			}
+			res = append(res, n)
+		}
+		return res
+	}
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.TrimSpace(t) == tag {
+			return true
+		}
+	}
+	return false
+}
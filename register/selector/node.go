@@ -0,0 +1,57 @@
+package selector
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jiushengTech/common/register"
+	"github.com/jiushengTech/kratos/v2/selector"
+)
+
+// defaultWeight is the weight of nodes without weight, the same as nacos and kratos.
+const defaultWeight = 100
+
+var _ selector.WeightedNode = (*node)(nil)
+
+// node is a selector node with the weight parsed from metadata.
+type node struct {
+	selector.Node
+	weight   float64
+	lastPick atomic.Int64
+}
+
+func newNode(n selector.Node) *node {
+	wn := &node{Node: n, weight: defaultWeight}
+	if w := n.InitialWeight(); w != nil {
+		wn.weight = float64(*w)
+	} else if s, ok := n.Metadata()[register.MetadataWeight]; ok {
+		// nacos weights are float, which kratos selector.NewNode does not parse
+		if w, err := strconv.ParseFloat(s, 64); err == nil {
+			wn.weight = w
+		}
+	}
+	return wn
+}
+
+// Raw returns the original node.
+func (n *node) Raw() selector.Node {
+	return n.Node
+}
+
+// Weight is the node weight.
+func (n *node) Weight() float64 {
+	return n.weight
+}
+
+// Pick records the pick time.
+func (n *node) Pick() selector.DoneFunc {
+	n.lastPick.Store(time.Now().UnixNano())
+	return func(context.Context, selector.DoneInfo) {}
+}
+
+// PickElapsed is time elapsed since the latest pick.
+func (n *node) PickElapsed() time.Duration {
+	return time.Duration(time.Now().UnixNano() - n.lastPick.Load())
+}
@@ -0,0 +1,215 @@
+// Package selector is a kratos selector balancing nodes by the metadata of package register.
+//
+// Unhealthy nodes and nodes with a zero weight are skipped, the remaining nodes are
+// picked by smooth weighted round-robin. Nodes in the same cluster and zone as the caller
+// are preferred, falling back to the same cluster, the same zone and finally all nodes.
+// The nacos registry drops unhealthy instances by default, see nacos.WithUnhealthyInstances to keep
+// them for this selector.
+//
+//	selector.SetGlobalSelector(wselector.NewBuilder(wselector.WithCluster("sh"), wselector.WithZone("sh-a")))
+package selector
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jiushengTech/common/register"
+	"github.com/jiushengTech/kratos/v2/selector"
+)
+
+// Name is the selector name.
+const Name = "weighted"
+
+var (
+	_ selector.Selector = (*Selector)(nil)
+	_ selector.Builder  = (*Builder)(nil)
+)
+
+type options struct {
+	cluster string
+	zone    string
+	filters []selector.NodeFilter
+}
+
+// Option is selector option.
+type Option func(o *options)
+
+// WithCluster with local cluster option, nodes in the same cluster are preferred.
+func WithCluster(cluster string) Option {
+	return func(o *options) { o.cluster = cluster }
+}
+
+// WithZone with local zone option, nodes in the same zone are preferred.
+func WithZone(zone string) Option {
+	return func(o *options) { o.zone = zone }
+}
+
+// WithFilter with filters applied on every Select, before the filters passed to Select.
+func WithFilter(filters ...selector.NodeFilter) Option {
+	return func(o *options) { o.filters = append(o.filters, filters...) }
+}
+
+// Builder is selector builder.
+type Builder struct {
+	opts options
+}
+
+// NewBuilder returns a selector builder.
+func NewBuilder(opts ...Option) *Builder {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Builder{opts: o}
+}
+
+// New returns a selector.
+func New(opts ...Option) *Selector {
+	return NewBuilder(opts...).build()
+}
+
+// Build creates selector.
+func (b *Builder) Build() selector.Selector {
+	return b.build()
+}
+
+func (b *Builder) build() *Selector {
+	return &Selector{
+		opts:          b.opts,
+		currentWeight: make(map[string]float64),
+	}
+}
+
+// Selector is a weighted round-robin selector with cluster and zone preference.
+type Selector struct {
+	opts  options
+	nodes atomic.Value
+
+	mu            sync.Mutex
+	currentWeight map[string]float64
+}
+
+// Apply updates the nodes, the round-robin state of the removed nodes is dropped.
+func (s *Selector) Apply(nodes []selector.Node) {
+	weighted := make([]selector.Node, 0, len(nodes))
+	for _, n := range nodes {
+		weighted = append(weighted, newNode(n))
+	}
+	s.mu.Lock()
+	currentWeight := make(map[string]float64, len(nodes))
+	for _, n := range nodes {
+		currentWeight[n.Address()] = s.currentWeight[n.Address()]
+	}
+	s.currentWeight = currentWeight
+	s.mu.Unlock()
+	s.nodes.Store(weighted)
+}
+
+// Select picks a node.
+func (s *Selector) Select(ctx context.Context, opts ...selector.SelectOption) (selector.Node, selector.DoneFunc, error) {
+	nodes, ok := s.nodes.Load().([]selector.Node)
+	if !ok {
+		return nil, nil, selector.ErrNoAvailable
+	}
+	var o selector.SelectOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	filters := make([]selector.NodeFilter, 0, len(s.opts.filters)+len(o.NodeFilters)+1)
+	filters = append(filters, available)
+	filters = append(filters, s.opts.filters...)
+	filters = append(filters, o.NodeFilters...)
+	for _, f := range filters {
+		nodes = f(ctx, nodes)
+	}
+	nodes = s.prefer(nodes)
+	if len(nodes) == 0 {
+		return nil, nil, selector.ErrNoAvailable
+	}
+
+	selected := s.pick(nodes)
+	if p, ok := selector.FromPeerContext(ctx); ok {
+		p.Node = selected.Raw()
+	}
+	return selected.Raw(), selected.Pick(), nil
+}
+
+// prefer returns the nodes closest to the caller.
+func (s *Selector) prefer(nodes []selector.Node) []selector.Node {
+	if s.opts.cluster == "" && s.opts.zone == "" {
+		return nodes
+	}
+	tiers := []func(selector.Node) bool{
+		func(n selector.Node) bool { return s.sameCluster(n) && s.sameZone(n) },
+		s.sameCluster,
+		s.sameZone,
+	}
+	for _, match := range tiers {
+		res := make([]selector.Node, 0, len(nodes))
+		for _, n := range nodes {
+			if match(n) {
+				res = append(res, n)
+			}
+		}
+		if len(res) > 0 {
+			return res
+		}
+	}
+	return nodes
+}
+
+func (s *Selector) sameCluster(n selector.Node) bool {
+	return s.opts.cluster != "" && n.Metadata()[register.MetadataCluster] == s.opts.cluster
+}
+
+func (s *Selector) sameZone(n selector.Node) bool {
+	return s.opts.zone != "" && n.Metadata()[register.MetadataZone] == s.opts.zone
+}
+
+// pick is the smooth weighted round-robin of nginx.
+func (s *Selector) pick(nodes []selector.Node) *node {
+	var (
+		total    float64
+		selected *node
+		maxWt    float64
+	)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, n := range nodes {
+		wn := weightedNode(n)
+		total += wn.weight
+		cwt := s.currentWeight[wn.Address()] + wn.weight
+		s.currentWeight[wn.Address()] = cwt
+		if selected == nil || maxWt < cwt {
+			maxWt = cwt
+			selected = wn
+		}
+	}
+	s.currentWeight[selected.Address()] = maxWt - total
+	return selected
+}
+
+// available drops unhealthy nodes and nodes without weight.
+func available(_ context.Context, nodes []selector.Node) []selector.Node {
+	res := make([]selector.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if n.Metadata()[register.MetadataHealthy] == "false" || weightedNode(n).weight <= 0 {
+			continue
+		}
+		res = append(res, n)
+	}
+	return res
+}
+
+// weightedNode returns n as *node, the nodes returned by user filters are wrapped,
+// using their weight if they are weighted nodes, or the weight metadata.
+func weightedNode(n selector.Node) *node {
+	switch wn := n.(type) {
+	case *node:
+		return wn
+	case selector.WeightedNode:
+		return &node{Node: wn.Raw(), weight: wn.Weight()}
+	}
+	return newNode(n)
+}
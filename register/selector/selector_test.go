@@ -0,0 +1,153 @@
+package selector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jiushengTech/kratos/v2/registry"
+	"github.com/jiushengTech/kratos/v2/selector"
+)
+
+func newTestNode(addr, version string, md map[string]string) selector.Node {
+	return selector.NewNode("grpc", addr, &registry.ServiceInstance{
+		ID:        addr,
+		Name:      "test",
+		Version:   version,
+		Metadata:  md,
+		Endpoints: []string{"grpc://" + addr},
+	})
+}
+
+// count selects n times and counts the picked addresses.
+func count(t *testing.T, s selector.Selector, n int, opts ...selector.SelectOption) map[string]int {
+	t.Helper()
+	res := make(map[string]int)
+	for i := 0; i < n; i++ {
+		node, done, err := s.Select(context.Background(), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		done(context.Background(), selector.DoneInfo{})
+		res[node.Address()]++
+	}
+	return res
+}
+
+func TestSelector_Weighted(t *testing.T) {
+	s := New()
+	s.Apply([]selector.Node{
+		newTestNode("127.0.0.1:9000", "v1", map[string]string{"weight": "10"}),
+		newTestNode("127.0.0.1:9001", "v1", map[string]string{"weight": "30.0"}),
+		newTestNode("127.0.0.1:9002", "v1", map[string]string{"weight": "0"}),
+		newTestNode("127.0.0.1:9003", "v1", map[string]string{"weight": "50", "healthy": "false"}),
+	})
+	got := count(t, s, 40)
+	if got["127.0.0.1:9000"] != 10 || got["127.0.0.1:9001"] != 30 || len(got) != 2 {
+		t.Errorf("unexpected distribution %v", got)
+	}
+}
+
+func TestSelector_Prefer(t *testing.T) {
+	nodes := []selector.Node{
+		newTestNode("sh-a", "v1", map[string]string{"cluster": "sh", "zone": "a"}),
+		newTestNode("sh-b", "v1", map[string]string{"cluster": "sh", "zone": "b"}),
+		newTestNode("bj-a", "v1", map[string]string{"cluster": "bj", "zone": "a"}),
+		newTestNode("bj-c", "v1", map[string]string{"cluster": "bj", "zone": "c"}),
+	}
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{name: "clusterAndZone", opts: []Option{WithCluster("sh"), WithZone("a")}, want: []string{"sh-a"}},
+		{name: "cluster", opts: []Option{WithCluster("sh"), WithZone("c")}, want: []string{"sh-a", "sh-b"}},
+		{name: "zone", opts: []Option{WithCluster("gz"), WithZone("a")}, want: []string{"sh-a", "bj-a"}},
+		{name: "fallback", opts: []Option{WithCluster("gz"), WithZone("d")}, want: []string{"sh-a", "sh-b", "bj-a", "bj-c"}},
+		{name: "none", want: []string{"sh-a", "sh-b", "bj-a", "bj-c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewBuilder(tt.opts...).Build()
+			s.Apply(nodes)
+			got := count(t, s, 4*len(tt.want))
+			if len(got) != len(tt.want) {
+				t.Fatalf("expect %v, got %v", tt.want, got)
+			}
+			for _, addr := range tt.want {
+				if got[addr] != 4 {
+					t.Errorf("expect %s picked 4 times, got %v", addr, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSelector_Filter(t *testing.T) {
+	s := New(WithFilter(Metadata(map[string]string{"env": "prod"})))
+	s.Apply([]selector.Node{
+		newTestNode("v1", "v1.0.0", map[string]string{"env": "prod", "tags": "stable"}),
+		newTestNode("v2", "v2.0.0", map[string]string{"env": "prod", "tags": "gray, canary"}),
+		newTestNode("dev", "v2.0.0", map[string]string{"env": "dev", "tags": "canary"}),
+	})
+
+	got := count(t, s, 4, selector.WithNodeFilter(Version("v2.0.0")))
+	if got["v2"] != 4 {
+		t.Errorf("expect only v2 picked, got %v", got)
+	}
+	got = count(t, s, 4, selector.WithNodeFilter(Tag("canary")))
+	if got["v2"] != 4 {
+		t.Errorf("expect only v2 picked, got %v", got)
+	}
+	got = count(t, s, 4, selector.WithNodeFilter(Version("v1.0.0", "v2.0.0")))
+	if got["v1"] != 2 || got["v2"] != 2 {
+		t.Errorf("expect v1 and v2 picked, got %v", got)
+	}
+	if _, _, err := s.Select(context.Background(), selector.WithNodeFilter(Tag("canary", "stable"))); !errors.Is(err, selector.ErrNoAvailable) {
+		t.Errorf("expect ErrNoAvailable, got %v", err)
+	}
+}
+
+func TestSelector_NoNodes(t *testing.T) {
+	if _, _, err := New().Select(context.Background()); !errors.Is(err, selector.ErrNoAvailable) {
+		t.Errorf("expect ErrNoAvailable, got %v", err)
+	}
+}
+
+// rawFilter returns the nodes of its own type, like the filters unwrapping the nodes.
+func rawFilter(_ context.Context, nodes []selector.Node) []selector.Node {
+	res := make([]selector.Node, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, selector.NewNode(n.Scheme(), n.Address(), &registry.ServiceInstance{
+			ID:       n.Address(),
+			Name:     n.ServiceName(),
+			Metadata: n.Metadata(),
+		}))
+	}
+	return res
+}
+
+func TestSelector_ForeignNodes(t *testing.T) {
+	s := New(WithFilter(rawFilter))
+	s.Apply([]selector.Node{
+		newTestNode("127.0.0.1:9000", "v1", map[string]string{"weight": "10"}),
+		newTestNode("127.0.0.1:9001", "v1", map[string]string{"weight": "30"}),
+	})
+	got := count(t, s, 40)
+	if got["127.0.0.1:9000"] != 10 || got["127.0.0.1:9001"] != 30 {
+		t.Errorf("unexpected distribution %v", got)
+	}
+}
+
+func TestSelector_ApplyPrunes(t *testing.T) {
+	s := New()
+	s.Apply([]selector.Node{newTestNode("127.0.0.1:9000", "v1", nil), newTestNode("127.0.0.1:9001", "v1", nil)})
+	count(t, s, 3)
+	s.Apply([]selector.Node{newTestNode("127.0.0.1:9001", "v1", nil)})
+	count(t, s, 1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.currentWeight["127.0.0.1:9000"]; ok || len(s.currentWeight) != 1 {
+		t.Errorf("expect the removed node pruned, got %v", s.currentWeight)
+	}
+}
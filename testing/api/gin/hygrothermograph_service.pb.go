@@ -0,0 +1,72 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: testing/api/gin/hygrothermograph_service.proto
+
+package ginapi
+
+import (
+	protobuf "github.com/jiushengTech/common/testing/api/protobuf"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_testing_api_gin_hygrothermograph_service_proto protoreflect.FileDescriptor
+
+const file_testing_api_gin_hygrothermograph_service_proto_rawDesc = "" +
+	"\n" +
+	".testing/api/gin/hygrothermograph_service.proto\x12\x0ftesting.api.gin\x1a\x1cgoogle/api/annotations.proto\x1a+testing/api/protobuf/hygrothermograph.proto2\xcd\x02\n" +
+	"\x17HygrothermographService\x12}\n" +
+	"\x13GetHygrothermograph\x12'.testing.api.GetHygrothermographRequest\x1a\x1d.testing.api.Hygrothermograph\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/hygrothermograph/{id}\x12\xb2\x01\n" +
+	"\x16UpdateHygrothermograph\x12*.testing.api.UpdateHygrothermographRequest\x1a\x1d.testing.api.Hygrothermograph\"M\x82\xd3\xe4\x93\x02G:\x10hygrothermographZ\x1b:\x01*\"\x16/hygrothermograph/{id}\x1a\x16/hygrothermograph/{id}B7Z5github.com/jiushengTech/common/testing/api/gin;ginapib\x06proto3"
+
+var file_testing_api_gin_hygrothermograph_service_proto_goTypes = []any{
+	(*protobuf.GetHygrothermographRequest)(nil),    // 0: testing.api.GetHygrothermographRequest
+	(*protobuf.UpdateHygrothermographRequest)(nil), // 1: testing.api.UpdateHygrothermographRequest
+	(*protobuf.Hygrothermograph)(nil),              // 2: testing.api.Hygrothermograph
+}
+var file_testing_api_gin_hygrothermograph_service_proto_depIdxs = []int32{
+	0, // 0: testing.api.gin.HygrothermographService.GetHygrothermograph:input_type -> testing.api.GetHygrothermographRequest
+	1, // 1: testing.api.gin.HygrothermographService.UpdateHygrothermograph:input_type -> testing.api.UpdateHygrothermographRequest
+	2, // 2: testing.api.gin.HygrothermographService.GetHygrothermograph:output_type -> testing.api.Hygrothermograph
+	2, // 3: testing.api.gin.HygrothermographService.UpdateHygrothermograph:output_type -> testing.api.Hygrothermograph
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_testing_api_gin_hygrothermograph_service_proto_init() }
+func file_testing_api_gin_hygrothermograph_service_proto_init() {
+	if File_testing_api_gin_hygrothermograph_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_testing_api_gin_hygrothermograph_service_proto_rawDesc), len(file_testing_api_gin_hygrothermograph_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_testing_api_gin_hygrothermograph_service_proto_goTypes,
+		DependencyIndexes: file_testing_api_gin_hygrothermograph_service_proto_depIdxs,
+	}.Build()
+	File_testing_api_gin_hygrothermograph_service_proto = out.File
+	file_testing_api_gin_hygrothermograph_service_proto_goTypes = nil
+	file_testing_api_gin_hygrothermograph_service_proto_depIdxs = nil
+}
@@ -0,0 +1,272 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: testing/api/protobuf/hygrothermograph.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Hygrothermograph is the humidity and temperature reported by a hygrothermograph.
+type Hygrothermograph struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Humidity      string                 `protobuf:"bytes,1,opt,name=humidity,proto3" json:"humidity,omitempty"`
+	Temperature   string                 `protobuf:"bytes,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Unit          string                 `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hygrothermograph) Reset() {
+	*x = Hygrothermograph{}
+	mi := &file_testing_api_protobuf_hygrothermograph_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hygrothermograph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hygrothermograph) ProtoMessage() {}
+
+func (x *Hygrothermograph) ProtoReflect() protoreflect.Message {
+	mi := &file_testing_api_protobuf_hygrothermograph_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hygrothermograph.ProtoReflect.Descriptor instead.
+func (*Hygrothermograph) Descriptor() ([]byte, []int) {
+	return file_testing_api_protobuf_hygrothermograph_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Hygrothermograph) GetHumidity() string {
+	if x != nil {
+		return x.Humidity
+	}
+	return ""
+}
+
+func (x *Hygrothermograph) GetTemperature() string {
+	if x != nil {
+		return x.Temperature
+	}
+	return ""
+}
+
+func (x *Hygrothermograph) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Hygrothermograph) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type GetHygrothermographRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Unit          string                 `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHygrothermographRequest) Reset() {
+	*x = GetHygrothermographRequest{}
+	mi := &file_testing_api_protobuf_hygrothermograph_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHygrothermographRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHygrothermographRequest) ProtoMessage() {}
+
+func (x *GetHygrothermographRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_testing_api_protobuf_hygrothermograph_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHygrothermographRequest.ProtoReflect.Descriptor instead.
+func (*GetHygrothermographRequest) Descriptor() ([]byte, []int) {
+	return file_testing_api_protobuf_hygrothermograph_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetHygrothermographRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetHygrothermographRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type UpdateHygrothermographRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Unit             string                 `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	Hygrothermograph *Hygrothermograph      `protobuf:"bytes,3,opt,name=hygrothermograph,proto3" json:"hygrothermograph,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdateHygrothermographRequest) Reset() {
+	*x = UpdateHygrothermographRequest{}
+	mi := &file_testing_api_protobuf_hygrothermograph_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateHygrothermographRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateHygrothermographRequest) ProtoMessage() {}
+
+func (x *UpdateHygrothermographRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_testing_api_protobuf_hygrothermograph_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateHygrothermographRequest.ProtoReflect.Descriptor instead.
+func (*UpdateHygrothermographRequest) Descriptor() ([]byte, []int) {
+	return file_testing_api_protobuf_hygrothermograph_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateHygrothermographRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateHygrothermographRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *UpdateHygrothermographRequest) GetHygrothermograph() *Hygrothermograph {
+	if x != nil {
+		return x.Hygrothermograph
+	}
+	return nil
+}
+
+var File_testing_api_protobuf_hygrothermograph_proto protoreflect.FileDescriptor
+
+const file_testing_api_protobuf_hygrothermograph_proto_rawDesc = "" +
+	"\n" +
+	"+testing/api/protobuf/hygrothermograph.proto\x12\vtesting.api\"t\n" +
+	"\x10Hygrothermograph\x12\x1a\n" +
+	"\bhumidity\x18\x01 \x01(\tR\bhumidity\x12 \n" +
+	"\vtemperature\x18\x02 \x01(\tR\vtemperature\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x12\n" +
+	"\x04unit\x18\x04 \x01(\tR\x04unit\"@\n" +
+	"\x1aGetHygrothermographRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04unit\x18\x02 \x01(\tR\x04unit\"\x8e\x01\n" +
+	"\x1dUpdateHygrothermographRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04unit\x18\x02 \x01(\tR\x04unit\x12I\n" +
+	"\x10hygrothermograph\x18\x03 \x01(\v2\x1d.testing.api.HygrothermographR\x10hygrothermographB9Z7github.com/jiushengTech/common/testing/api/protobuf;apib\x06proto3"
+
+var (
+	file_testing_api_protobuf_hygrothermograph_proto_rawDescOnce sync.Once
+	file_testing_api_protobuf_hygrothermograph_proto_rawDescData []byte
+)
+
+func file_testing_api_protobuf_hygrothermograph_proto_rawDescGZIP() []byte {
+	file_testing_api_protobuf_hygrothermograph_proto_rawDescOnce.Do(func() {
+		file_testing_api_protobuf_hygrothermograph_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_testing_api_protobuf_hygrothermograph_proto_rawDesc), len(file_testing_api_protobuf_hygrothermograph_proto_rawDesc)))
+	})
+	return file_testing_api_protobuf_hygrothermograph_proto_rawDescData
+}
+
+var file_testing_api_protobuf_hygrothermograph_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_testing_api_protobuf_hygrothermograph_proto_goTypes = []any{
+	(*Hygrothermograph)(nil),              // 0: testing.api.Hygrothermograph
+	(*GetHygrothermographRequest)(nil),    // 1: testing.api.GetHygrothermographRequest
+	(*UpdateHygrothermographRequest)(nil), // 2: testing.api.UpdateHygrothermographRequest
+}
+var file_testing_api_protobuf_hygrothermograph_proto_depIdxs = []int32{
+	0, // 0: testing.api.UpdateHygrothermographRequest.hygrothermograph:type_name -> testing.api.Hygrothermograph
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_testing_api_protobuf_hygrothermograph_proto_init() }
+func file_testing_api_protobuf_hygrothermograph_proto_init() {
+	if File_testing_api_protobuf_hygrothermograph_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_testing_api_protobuf_hygrothermograph_proto_rawDesc), len(file_testing_api_protobuf_hygrothermograph_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_testing_api_protobuf_hygrothermograph_proto_goTypes,
+		DependencyIndexes: file_testing_api_protobuf_hygrothermograph_proto_depIdxs,
+		MessageInfos:      file_testing_api_protobuf_hygrothermograph_proto_msgTypes,
+	}.Build()
+	File_testing_api_protobuf_hygrothermograph_proto = out.File
+	file_testing_api_protobuf_hygrothermograph_proto_goTypes = nil
+	file_testing_api_protobuf_hygrothermograph_proto_depIdxs = nil
+}
@@ -0,0 +1,183 @@
+package gin
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entrans "github.com/go-playground/validator/v10/translations/en"
+	zhtrans "github.com/go-playground/validator/v10/translations/zh"
+	"google.golang.org/protobuf/proto"
+)
+
+// ValidatorReason is the reason of the validation errors, the field errors are in the metadata.
+const ValidatorReason = "VALIDATOR"
+
+var (
+	validateOnce sync.Once
+	validate     *validator.Validate
+	translator   *ut.UniversalTranslator
+)
+
+// validatable is implemented by the messages generated by protoc-gen-validate.
+type validatable interface {
+	Validate() error
+}
+
+// pgvError is the field error of protoc-gen-validate.
+type pgvError interface {
+	Field() string
+	Reason() string
+}
+
+// Bind merges the body, query, header and path parameters, in the order of precedence, into a new T,
+// and validates it by the binding tags and its Validate method.
+// The validation errors are returned as a bad request whose metadata maps the fields to the messages,
+// translated into Chinese or English by the Accept-Language header.
+func Bind[T any](c *Context) (*T, error) {
+	v := new(T)
+	if err := c.BindAll(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// BindAll merges the body, query, header and path parameters into v, and validates it.
+// Proto messages are bound like the kratos http server, and other structs by the form, header and uri tags,
+// the header tags are matched in the canonical or lower case, e.g. X-Token or x-token.
+func (c *Context) BindAll(v any) error {
+	// the body is decoded first, as the proto json codec resets the message
+	if hasBody(c.Request) {
+		if err := c.Bind(v); err != nil {
+			return err
+		}
+	}
+	if _, ok := v.(proto.Message); ok {
+		if err := c.BindQuery(v); err != nil {
+			return err
+		}
+		if err := c.BindVars(v); err != nil {
+			return err
+		}
+	} else {
+		if err := binding.MapFormWithTag(v, c.Request.URL.Query(), "form"); err != nil {
+			return errors.BadRequest("CODEC", err.Error())
+		}
+		headers := make(map[string][]string, len(c.Request.Header)*2)
+		for k, vs := range c.Request.Header {
+			headers[k], headers[strings.ToLower(k)] = vs, vs
+		}
+		if err := binding.MapFormWithTag(v, headers, "header"); err != nil {
+			return errors.BadRequest("CODEC", err.Error())
+		}
+		params := make(map[string][]string, len(c.Params))
+		for _, p := range c.Params {
+			params[p.Key] = append(params[p.Key], p.Value)
+		}
+		if err := binding.MapFormWithTag(v, params, "uri"); err != nil {
+			return errors.BadRequest("CODEC", err.Error())
+		}
+	}
+	return Validate(c.GetHeader("Accept-Language"), v)
+}
+
+// hasBody reports whether the request has a body to decode, plain GET and DELETE requests have no Content-Type
+// and would be rejected by the decoder.
+func hasBody(r *http.Request) bool {
+	if r.ContentLength != 0 {
+		return true
+	}
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	}
+	return false
+}
+
+// Validate validates v by the binding tags and its Validate method,
+// the field errors are translated into Chinese if lang is zh, or English.
+func Validate(lang string, v any) error {
+	validateOnce.Do(initValidator)
+	fields := make(map[string]string)
+	if rv := reflect.Indirect(reflect.ValueOf(v)); rv.Kind() == reflect.Struct {
+		if err := validate.Struct(v); err != nil {
+			var errs validator.ValidationErrors
+			if !errors.As(err, &errs) {
+				return errors.BadRequest(ValidatorReason, err.Error())
+			}
+			trans, _ := translator.GetTranslator(locale(lang))
+			for _, fe := range errs {
+				fields[fieldName(fe.Namespace())] = fe.Translate(trans)
+			}
+		}
+	}
+	if m, ok := v.(validatable); ok {
+		if err := m.Validate(); err != nil {
+			all := []error{err}
+			if multi, ok := err.(interface{ AllErrors() []error }); ok {
+				all = multi.AllErrors()
+			}
+			for _, e := range all {
+				if fe, ok := e.(pgvError); ok {
+					fields[fe.Field()] = fe.Reason()
+				} else {
+					return errors.BadRequest(ValidatorReason, err.Error())
+				}
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(fields))
+	for _, msg := range fields {
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+	return errors.BadRequest(ValidatorReason, strings.Join(messages, "; ")).WithMetadata(fields)
+}
+
+func initValidator() {
+	validate = validator.New(validator.WithRequiredStructEnabled())
+	validate.SetTagName("binding")
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "form", "uri", "header"} {
+			if name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name != "" && name != "-" {
+				return name
+			}
+		}
+		return f.Name
+	})
+	enLocale, zhLocale := en.New(), zh.New()
+	translator = ut.New(enLocale, enLocale, zhLocale)
+	enTrans, _ := translator.GetTranslator(enLocale.Locale())
+	zhTrans, _ := translator.GetTranslator(zhLocale.Locale())
+	_ = entrans.RegisterDefaultTranslations(validate, enTrans)
+	_ = zhtrans.RegisterDefaultTranslations(validate, zhTrans)
+}
+
+// locale returns zh if the preferred language of the Accept-Language header is Chinese, or en.
+func locale(acceptLanguage string) string {
+	lang, _, _ := strings.Cut(acceptLanguage, ",")
+	lang, _, _ = strings.Cut(lang, ";")
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(lang)), "zh") {
+		return "zh"
+	}
+	return "en"
+}
+
+// fieldName trims the struct name of the namespace, e.g. User.address.city is address.city.
+func fieldName(namespace string) string {
+	if _, name, ok := strings.Cut(namespace, "."); ok {
+		return name
+	}
+	return namespace
+}
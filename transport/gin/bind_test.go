@@ -0,0 +1,150 @@
+package gin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	api "github.com/jiushengTech/common/testing/api/protobuf"
+)
+
+type updateUserRequest struct {
+	ID      int64  `uri:"id" json:"-" binding:"required"`
+	Token   string `header:"X-Token" json:"-" binding:"required"`
+	Page    int    `form:"page" json:"-" binding:"gte=1"`
+	Name    string `json:"name" binding:"required,max=8"`
+	Email   string `json:"email" binding:"omitempty,email"`
+	Address struct {
+		City string `json:"city" binding:"required"`
+	} `json:"address"`
+}
+
+// fieldError is like the field errors generated by protoc-gen-validate.
+type fieldError struct {
+	field, reason string
+}
+
+func (e fieldError) Error() string  { return e.field + ": " + e.reason }
+func (e fieldError) Field() string  { return e.field }
+func (e fieldError) Reason() string { return e.reason }
+
+type rangeRequest struct {
+	From int `form:"from"`
+	To   int `form:"to"`
+}
+
+func (r *rangeRequest) Validate() error {
+	if r.From > r.To {
+		return fieldError{field: "from", reason: "must not be greater than to"}
+	}
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	srv := NewServer(WithResponseEncoder(ResponseEncoder), WithErrorEncoder(ErrorEncoder))
+	srv.PUT("/user/:id", srv.Handle(func(c *Context) error {
+		return c.Returns(Bind[updateUserRequest](c))
+	}))
+	srv.GET("/range", srv.Handle(func(c *Context) error {
+		return c.Returns(Bind[rangeRequest](c))
+	}))
+	srv.PUT("/hygrothermograph/:id", srv.Handle(func(c *Context) error {
+		return c.Returns(Bind[api.UpdateHygrothermographRequest](c))
+	}))
+
+	do := func(method, target, body string, header map[string]string) (int, *Response) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := serveRequest(srv, req)
+		resp := new(Response)
+		if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+			t.Fatalf("%v: %s", err, w.Body.String())
+		}
+		return w.Code, resp
+	}
+
+	code, resp := do(http.MethodPut, "/user/7?page=2", `{"name":"alice","address":{"city":"hz"}}`, map[string]string{"X-Token": "t"})
+	var user updateUserRequest
+	_ = json.Unmarshal(resp.Data, &user)
+	if code != http.StatusOK || user.Name != "alice" || user.Address.City != "hz" {
+		t.Errorf("unexpected response %d %+v", code, resp)
+	}
+
+	tests := []struct {
+		name   string
+		lang   string
+		fields map[string]string
+	}{
+		{name: "en", lang: "en-US,en;q=0.9", fields: map[string]string{
+			"X-Token":      "X-Token is a required field",
+			"page":         "page must be 1 or greater",
+			"name":         "name must be a maximum of 8 characters in length",
+			"email":        "email must be a valid email address",
+			"address.city": "city is a required field",
+		}},
+		{name: "zh", lang: "zh-CN,zh;q=0.9", fields: map[string]string{
+			"X-Token":      "X-Token为必填字段",
+			"page":         "page必须大于或等于1",
+			"name":         "name长度不能超过8个字符",
+			"email":        "email必须是一个有效的邮箱",
+			"address.city": "city为必填字段",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, resp := do(http.MethodPut, "/user/7?page=0", `{"name":"alice-in-wonderland","email":"x"}`, map[string]string{"Accept-Language": tt.lang})
+			if code != http.StatusBadRequest || resp.Reason != ValidatorReason || len(resp.Metadata) != len(tt.fields) {
+				t.Fatalf("unexpected response %d %+v", code, resp)
+			}
+			for field, msg := range tt.fields {
+				if resp.Metadata[field] != msg {
+					t.Errorf("expect %s: %q, got %q", field, msg, resp.Metadata[field])
+				}
+			}
+		})
+	}
+
+	// no body and no Content-Type
+	code, resp = do(http.MethodGet, "/range?from=1&to=2", "", nil)
+	var r rangeRequest
+	_ = json.Unmarshal(resp.Data, &r)
+	if code != http.StatusOK || r.From != 1 || r.To != 2 {
+		t.Errorf("unexpected response of the GET request %d %+v", code, resp)
+	}
+	if code, resp = do(http.MethodGet, "/range?from=2&to=1", "", nil); code != http.StatusBadRequest || resp.Metadata["from"] != "must not be greater than to" {
+		t.Errorf("expect the Validate error, got %d %+v", code, resp)
+	}
+	if code, _ = do(http.MethodGet, "/range?from=x", "", nil); code != http.StatusBadRequest {
+		t.Errorf("expect the bad query rejected, got %d", code)
+	}
+
+	code, resp = do(http.MethodPut, "/hygrothermograph/3?unit=c", `{"hygrothermograph":{"humidity":"60"}}`, nil)
+	var in api.UpdateHygrothermographRequest
+	if err := json.Unmarshal(resp.Data, &in); err != nil || code != http.StatusOK || in.Id != "3" || in.Unit != "c" || in.Hygrothermograph.GetHumidity() != "60" {
+		t.Errorf("unexpected proto response %d %s", code, resp.Data)
+	}
+}
+
+func TestBind_GinContext(t *testing.T) {
+	srv := NewServer()
+	srv.GET("/", func(c *gin.Context) {
+		_, err := Bind[rangeRequest](srv.WrapContext(c))
+		if err != nil {
+			Fail(c, err)
+			return
+		}
+		Success(c, nil)
+	})
+	if w := serve(srv, http.MethodGet, "/?from=3&to=1", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("expect 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,122 @@
+package gin
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport/http/binding"
+)
+
+// SupportPackageIsVersion1 These constants should not be referenced from any other code.
+const SupportPackageIsVersion1 = true
+
+// HandlerFunc is a gin handler returning an error, the error is rendered by the error encoder.
+type HandlerFunc func(*Context) error
+
+// Context is a gin context bound to the server decoder, encoders and middlewares.
+type Context struct {
+	*gin.Context
+	srv *Server
+}
+
+// Vars returns the path parameters, the leading slash of catch-all parameters is trimmed.
+func (c *Context) Vars() url.Values {
+	vars := make(url.Values, len(c.Params))
+	for _, p := range c.Params {
+		vars.Add(p.Key, strings.TrimPrefix(p.Value, "/"))
+	}
+	return vars
+}
+
+// Bind decodes the request body into v with the request decoder.
+func (c *Context) Bind(v any) error {
+	return c.srv.dec(c.Request, v)
+}
+
+// BindVars decodes the path parameters into v.
+func (c *Context) BindVars(v any) error {
+	return binding.BindQuery(c.Vars(), v)
+}
+
+// BindQuery decodes the query parameters into v.
+func (c *Context) BindQuery(v any) error {
+	return binding.BindQuery(c.Request.URL.Query(), v)
+}
+
+// BindForm decodes the form parameters into v.
+func (c *Context) BindForm(v any) error {
+	return binding.BindForm(c.Request, v)
+}
+
+// Middleware wraps h with the server middlewares.
+func (c *Context) Middleware(h middleware.Handler) middleware.Handler {
+	return middleware.Chain(c.srv.ms...)(h)
+}
+
+// Result encodes v with the response encoder and the status code.
+func (c *Context) Result(code int, v any) error {
+	c.Writer.WriteHeader(code)
+	return c.srv.enc(c.Writer, c.Request, v)
+}
+
+// Returns encodes v with the response encoder, or returns err.
+func (c *Context) Returns(v any, err error) error {
+	if err != nil {
+		return err
+	}
+	return c.srv.enc(c.Writer, c.Request, v)
+}
+
+// WrapContext binds c to the server decoder, encoders and middlewares, for the plain gin handlers.
+func (s *Server) WrapContext(c *gin.Context) *Context {
+	return &Context{Context: c, srv: s}
+}
+
+// Handle converts h to a gin handler, the error returned is rendered by the error encoder.
+func (s *Server) Handle(h HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h(s.WrapContext(c)); err != nil {
+			s.abort(c, err)
+		}
+	}
+}
+
+// Middleware converts the kratos middlewares to a gin middleware running around the next handlers.
+// Errors of the next handlers, added by c.Error, are returned to the middlewares, and the errors
+// not rendered yet are rendered by the error encoder.
+func (s *Server) Middleware(m ...middleware.Middleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var called bool
+		next := func(ctx context.Context, req any) (any, error) {
+			called = true
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+			if err := c.Errors.Last(); err != nil {
+				return nil, err.Err
+			}
+			return nil, nil
+		}
+		_, err := middleware.Chain(m...)(next)(c.Request.Context(), c.Request)
+		if err != nil {
+			s.abort(c, err)
+			return
+		}
+		if !called {
+			c.Abort()
+		}
+	}
+}
+
+// abort renders err by the error encoder unless the response is written, and aborts the next handlers.
+func (s *Server) abort(c *gin.Context, err error) {
+	if last := c.Errors.Last(); last == nil || last.Err != err {
+		_ = c.Error(err)
+	}
+	if !c.Writer.Written() {
+		s.ene(c.Writer, c.Request, err)
+	}
+	c.Abort()
+}
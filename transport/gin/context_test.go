@@ -0,0 +1,142 @@
+package gin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/middleware/recovery"
+
+	api "github.com/jiushengTech/common/testing/api/protobuf"
+)
+
+func serve(srv *Server, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	return w
+}
+
+func TestContext_BindResult(t *testing.T) {
+	var called []string
+	trace := func(name string) middleware.Middleware {
+		return func(h middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				called = append(called, name)
+				return h(ctx, req)
+			}
+		}
+	}
+	srv := NewServer(WithMiddleware(trace("server")))
+	srv.POST("/hygrothermograph/:humidity", srv.Handle(func(c *Context) error {
+		var in api.Hygrothermograph
+		if err := c.Bind(&in); err != nil {
+			return err
+		}
+		if err := c.BindVars(&in); err != nil {
+			return err
+		}
+		h := c.Middleware(func(ctx context.Context, req any) (any, error) {
+			return req, nil
+		})
+		out, err := h(c, &in)
+		if err != nil {
+			return err
+		}
+		return c.Result(http.StatusCreated, out)
+	}))
+
+	w := serve(srv, http.MethodPost, "/hygrothermograph/60", `{"temperature":"25"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expect status 201, got %d", w.Code)
+	}
+	var out api.Hygrothermograph
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Humidity != "60" || out.Temperature != "25" {
+		t.Errorf("unexpected reply %+v", &out)
+	}
+	if len(called) != 1 || called[0] != "server" {
+		t.Errorf("expect the server middleware called, got %v", called)
+	}
+}
+
+func TestContext_Error(t *testing.T) {
+	srv := NewServer()
+	srv.GET("/error", srv.Handle(func(c *Context) error {
+		return c.Returns(nil, errors.NotFound("NOT_FOUND", "hygrothermograph not found"))
+	}))
+
+	w := serve(srv, http.MethodGet, "/error", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expect status 404, got %d", w.Code)
+	}
+	e := new(errors.Error)
+	if err := json.Unmarshal(w.Body.Bytes(), e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Reason != "NOT_FOUND" || e.Message != "hygrothermograph not found" {
+		t.Errorf("unexpected error %+v", e)
+	}
+}
+
+func TestServer_Middleware(t *testing.T) {
+	auth := func(h middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if req.(*http.Request).Header.Get("Authorization") == "" {
+				return nil, errors.Unauthorized("UNAUTHORIZED", "missing token")
+			}
+			return h(ctx, req)
+		}
+	}
+	srv := NewServer()
+	srv.Use(srv.Middleware(recovery.Recovery(), auth))
+	srv.GET("/ok", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	srv.GET("/panic", func(c *gin.Context) { panic("boom") })
+	srv.GET("/error", func(c *gin.Context) { _ = c.Error(errors.Conflict("CONFLICT", "")) })
+
+	if w := serve(srv, http.MethodGet, "/ok", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expect status 401 without token, got %d", w.Code)
+	}
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/ok", http.StatusOK},
+		{"/panic", http.StatusInternalServerError},
+		{"/error", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%s: expect status %d, got %d", tt.path, tt.code, w.Code)
+		}
+	}
+}
+
+func TestServer_Filter(t *testing.T) {
+	filter := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Filter", "1")
+			next.ServeHTTP(w, r)
+		})
+	}
+	srv := NewServer(WithFilter(filter))
+	srv.GET("/ok", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	if w := serve(srv, http.MethodGet, "/ok", ""); w.Header().Get("X-Filter") != "1" {
+		t.Errorf("expect the filter applied, got header %v", w.Header())
+	}
+}
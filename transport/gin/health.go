@@ -0,0 +1,128 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HealthzPath 存活检查的路由，只反映进程是否存活，不执行检查项
+	HealthzPath = "/healthz"
+	// ReadyzPath 就绪检查的路由，执行所有检查项，Stop 开始后返回失败
+	ReadyzPath = "/readyz"
+)
+
+// HealthChecker 健康检查项，如数据库、MQTT 连接、注册中心
+type HealthChecker struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthStatus 健康检查的响应，Checks 为各检查项的结果
+type HealthStatus struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+const (
+	healthOK   = "ok"
+	healthFail = "fail"
+	stopping   = "stopping"
+)
+
+// WithHealth 注册 /healthz 和 /readyz。/healthz 总是返回 200，Stop 开始后 status 为 stopping，
+// 依赖故障不会导致存活检查失败而重启；/readyz 并发执行所有检查项，全部通过时返回 200，否则返回 503。
+// 每个检查项的超时见 WithHealthTimeout，超时的检查项视为失败
+func WithHealth(checkers ...HealthChecker) ServerOption {
+	return func(s *Server) {
+		s.checkers = append(s.checkers, checkers...)
+		s.Engine.GET(HealthzPath, s.healthz)
+		s.Engine.GET(ReadyzPath, s.readyz)
+	}
+}
+
+// WithHealthTimeout 设置每个检查项的超时时间，默认 3 秒，小于等于 0 时只受请求的 context 控制
+func WithHealthTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.healthTimeout = d
+	}
+}
+
+// AddHealthChecker 添加检查项，用于服务创建后才能获得的依赖
+func (s *Server) AddHealthChecker(checkers ...HealthChecker) {
+	s.checkersMu.Lock()
+	defer s.checkersMu.Unlock()
+	s.checkers = append(s.checkers, checkers...)
+}
+
+func (s *Server) healthz(c *gin.Context) {
+	status := &HealthStatus{Status: healthOK}
+	if s.stopping.Load() {
+		status.Status = stopping
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+func (s *Server) readyz(c *gin.Context) {
+	status := s.check(c.Request.Context())
+	if s.stopping.Load() {
+		status.Status = stopping
+	}
+	code := http.StatusOK
+	if status.Status != healthOK {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, status)
+}
+
+func (s *Server) check(ctx context.Context) *HealthStatus {
+	s.checkersMu.RLock()
+	checkers := s.checkers
+	s.checkersMu.RUnlock()
+
+	status := &HealthStatus{Status: healthOK, Checks: make(map[string]string, len(checkers))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, checker := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.checkOne(ctx, checker)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				status.Checks[checker.Name] = err.Error()
+				status.Status = healthFail
+				return
+			}
+			status.Checks[checker.Name] = healthOK
+		}()
+	}
+	wg.Wait()
+	return status
+}
+
+// checkOne runs the checker with the health timeout, the checkers ignoring ctx are abandoned once it is done.
+func (s *Server) checkOne(ctx context.Context, checker HealthChecker) error {
+	if s.healthTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.healthTimeout)
+		defer cancel()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- checker.Check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
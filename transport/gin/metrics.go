@@ -0,0 +1,92 @@
+package gin
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsPath 指标的路由
+const MetricsPath = "/metrics"
+
+// unmatchedRoute 未匹配路由的请求的 route 标签，避免以请求路径作为标签值
+const unmatchedRoute = "unmatched"
+
+type serverMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+}
+
+// WithMetrics 统计请求数、耗时和处理中的请求数，标签为路由模板、方法和状态码，并在 /metrics 以 Prometheus 文本格式输出。
+// reg 为空时使用新的 Registry 并注册 Go 运行时和进程的指标，多个 Server 可共用同一个 reg
+func WithMetrics(reg *prometheus.Registry) ServerOption {
+	return func(s *Server) {
+		if reg == nil {
+			reg = prometheus.NewRegistry()
+			reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+		}
+		m := &serverMetrics{
+			requests: register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "gin",
+				Subsystem: "http",
+				Name:      "requests_total",
+				Help:      "The total number of processed requests.",
+			}, []string{"route", "method", "status"})),
+			duration: register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: "gin",
+				Subsystem: "http",
+				Name:      "request_duration_seconds",
+				Help:      "The latency of processed requests.",
+				Buckets:   prometheus.DefBuckets,
+			}, []string{"route", "method", "status"})),
+			inFlight: register(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: "gin",
+				Subsystem: "http",
+				Name:      "requests_in_flight",
+				Help:      "The number of requests being processed.",
+			}, []string{"route", "method"})),
+		}
+		s.Engine.Use(m.handler())
+		s.Engine.GET(MetricsPath, gin.WrapH(promhttp.HandlerFor(reg, promhttp.HandlerOpts{})))
+	}
+}
+
+func (m *serverMetrics) handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+		inFlight := m.inFlight.WithLabelValues(route, method)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		c.Next()
+
+		status := strconv.Itoa(c.Writer.Status())
+		m.requests.WithLabelValues(route, method, status).Inc()
+		m.duration.WithLabelValues(route, method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// register registers c to reg, the collector registered already is reused.
+func register[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
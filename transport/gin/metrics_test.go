@@ -0,0 +1,97 @@
+package gin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestServer_Metrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	srv := NewServer(WithMetrics(reg))
+	srv.GET("/user/:id", func(c *gin.Context) { c.String(http.StatusOK, c.Param("id")) })
+
+	serve(srv, http.MethodGet, "/user/1", "")
+	serve(srv, http.MethodGet, "/user/2", "")
+	serve(srv, http.MethodGet, "/missing", "")
+
+	w := serve(srv, http.MethodGet, MetricsPath, "")
+	for _, want := range []string{
+		`gin_http_requests_total{method="GET",route="/user/:id",status="200"} 2`,
+		`gin_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`gin_http_request_duration_seconds_count{method="GET",route="/user/:id",status="200"} 2`,
+		`gin_http_requests_in_flight{method="GET",route="/metrics"} 1`,
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("expect %s in the metrics:\n%s", want, w.Body.String())
+		}
+	}
+
+	// the collectors are reused by the servers sharing the registry
+	NewServer(WithMetrics(reg))
+}
+
+func TestServer_Health(t *testing.T) {
+	var dbErr error
+	srv := NewServer(WithHealth(HealthChecker{Name: "db", Check: func(context.Context) error { return dbErr }}))
+	srv.AddHealthChecker(HealthChecker{Name: "mqtt", Check: func(context.Context) error { return nil }})
+
+	get := func(path string) (int, *HealthStatus) {
+		w := serve(srv, http.MethodGet, path, "")
+		status := new(HealthStatus)
+		if err := json.Unmarshal(w.Body.Bytes(), status); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, status
+	}
+
+	if code, status := get(ReadyzPath); code != http.StatusOK || status.Status != "ok" || len(status.Checks) != 2 {
+		t.Errorf("unexpected readiness %d %+v", code, status)
+	}
+	dbErr = errors.New("connection refused")
+	if code, status := get(ReadyzPath); code != http.StatusServiceUnavailable || status.Checks["db"] != "connection refused" || status.Checks["mqtt"] != "ok" {
+		t.Errorf("unexpected readiness %d %+v", code, status)
+	}
+	// a dependency outage doesn't fail the liveness
+	if code, status := get(HealthzPath); code != http.StatusOK || status.Status != "ok" || len(status.Checks) != 0 {
+		t.Errorf("unexpected liveness %d %+v", code, status)
+	}
+
+	dbErr = nil
+	_ = srv.Stop(context.Background())
+	if code, status := get(ReadyzPath); code != http.StatusServiceUnavailable || status.Status != "stopping" {
+		t.Errorf("expect not ready once stopping, got %d %+v", code, status)
+	}
+	if code, status := get(HealthzPath); code != http.StatusOK || status.Status != "stopping" {
+		t.Errorf("expect alive while stopping, got %d %+v", code, status)
+	}
+}
+
+func TestServer_HealthTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	srv := NewServer(WithHealthTimeout(50*time.Millisecond), WithHealth(
+		// ignores ctx, abandoned after the timeout
+		HealthChecker{Name: "hung", Check: func(context.Context) error { <-block; return nil }},
+		HealthChecker{Name: "mqtt", Check: func(context.Context) error { return nil }},
+	))
+	start := time.Now()
+	w := serve(srv, http.MethodGet, ReadyzPath, "")
+	status := new(HealthStatus)
+	if err := json.Unmarshal(w.Body.Bytes(), status); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusServiceUnavailable || status.Checks["hung"] != context.DeadlineExceeded.Error() || status.Checks["mqtt"] != "ok" {
+		t.Errorf("expect the hung check failed, got %d %+v", w.Code, status)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expect readiness answered after the check timeout, took %v", elapsed)
+	}
+}
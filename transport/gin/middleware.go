@@ -1,86 +1,332 @@
 package gin
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/go-kratos/kratos/v2/log"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
 	"os"
+	"regexp"
 	"runtime/debug"
 	"strings"
 	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware/recovery"
+	kHttp "github.com/go-kratos/kratos/v2/transport/http"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// GinLogger 接收gin框架默认的日志
-func GinLogger(logger log.Logger) gin.HandlerFunc {
+// maskedValue 脱敏字段替换后的值
+const maskedValue = "***"
+
+// LoggerOption GinLogger 的配置
+type LoggerOption func(*loggerOptions)
+
+type loggerOptions struct {
+	skipPaths     map[string]struct{}
+	requestBody   int
+	responseBody  int
+	mask          *regexp.Regexp
+	maskFields    map[string]struct{}
+	slowThreshold time.Duration
+}
+
+// WithLogSkipPaths 不记录的路由，与路由模板（如 /user/:id）或请求路径比较，如 /healthz、/metrics
+func WithLogSkipPaths(paths ...string) LoggerOption {
+	return func(o *loggerOptions) {
+		for _, p := range paths {
+			o.skipPaths[p] = struct{}{}
+		}
+	}
+}
+
+// WithLogRequestBody 记录请求体的前 limit 个字节，multipart 请求不记录
+func WithLogRequestBody(limit int) LoggerOption {
+	return func(o *loggerOptions) {
+		o.requestBody = limit
+	}
+}
+
+// WithLogResponseBody 记录响应体的前 limit 个字节
+func WithLogResponseBody(limit int) LoggerOption {
+	return func(o *loggerOptions) {
+		o.responseBody = limit
+	}
+}
+
+// WithLogMaskFields 脱敏的字段，JSON 和表单中的字段及同名的 query 参数替换为 ***，不区分大小写
+func WithLogMaskFields(fields ...string) LoggerOption {
+	return func(o *loggerOptions) {
+		for _, f := range fields {
+			o.maskFields[strings.ToLower(f)] = struct{}{}
+		}
+	}
+}
+
+// WithLogSlowThreshold 耗时超过 threshold 的请求以 warn 级别记录
+func WithLogSlowThreshold(threshold time.Duration) LoggerOption {
+	return func(o *loggerOptions) {
+		o.slowThreshold = threshold
+	}
+}
+
+// GinLogger 记录访问日志，包括请求 ID 和链路追踪 ID，5xx 的请求以 error 级别记录
+func GinLogger(logger log.Logger, opts ...LoggerOption) gin.HandlerFunc {
+	o := &loggerOptions{
+		skipPaths:  make(map[string]struct{}),
+		maskFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if len(o.maskFields) > 0 {
+		fields := make([]string, 0, len(o.maskFields))
+		for f := range o.maskFields {
+			fields = append(fields, regexp.QuoteMeta(f))
+		}
+		// "field": "value" / "field": 123 in JSON, and field=value in forms
+		o.mask = regexp.MustCompile(`(?i)("(?:` + strings.Join(fields, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)` +
+			`|((?:^|&)(?:` + strings.Join(fields, "|") + `)=)[^&]*`)
+	}
 	return func(c *gin.Context) {
+		if o.skip(c) {
+			c.Next()
+			return
+		}
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
+		traceID := traceIDFromContext(c.Request.Context())
+
+		var (
+			reqBody      string
+			reqTruncated bool
+		)
+		if o.requestBody > 0 {
+			reqBody, reqTruncated = captureRequestBody(c, o.requestBody)
+		}
+		var respBody *bodyWriter
+		if o.responseBody > 0 {
+			respBody = &bodyWriter{ResponseWriter: c.Writer, limit: o.responseBody}
+			c.Writer = respBody
+		}
+
 		c.Next()
 
 		cost := time.Since(start)
-		_ = logger.Log(log.LevelInfo,
-			"path", path,
-			"status", c.Writer.Status(),
+		if traceID == "" {
+			traceID = traceIDFromContext(c.Request.Context())
+		}
+		status := c.Writer.Status()
+		level := log.LevelInfo
+		if o.slowThreshold > 0 && cost > o.slowThreshold {
+			level = log.LevelWarn
+		}
+		if status >= http.StatusInternalServerError {
+			level = log.LevelError
+		}
+		kvs := []any{
+			"status", status,
 			"method", c.Request.Method,
 			"path", path,
-			"query", query,
+			"route", c.FullPath(),
+			"query", o.maskQuery(query),
 			"ip", c.ClientIP(),
 			"user-agent", c.Request.UserAgent(),
+			"request_id", requestIDFromGin(c),
+			"trace_id", traceID,
 			"errors", c.Errors.ByType(gin.ErrorTypePrivate).String(),
 			"cost", cost,
-		)
+		}
+		if o.slowThreshold > 0 && cost > o.slowThreshold {
+			kvs = append(kvs, "slow", true)
+		}
+		if o.requestBody > 0 {
+			kvs = append(kvs, "request_body", o.maskBody(reqBody, reqTruncated))
+		}
+		if respBody != nil {
+			kvs = append(kvs, "response_body", o.maskBody(respBody.buf.String(), respBody.truncated))
+		}
+		_ = logger.Log(level, kvs...)
+	}
+}
+
+func (o *loggerOptions) skip(c *gin.Context) bool {
+	if len(o.skipPaths) == 0 {
+		return false
+	}
+	if _, ok := o.skipPaths[c.FullPath()]; ok {
+		return true
+	}
+	_, ok := o.skipPaths[c.Request.URL.Path]
+	return ok
+}
+
+// maskBody masks the fields of body, and marks the truncated body by "...".
+func (o *loggerOptions) maskBody(body string, truncated bool) string {
+	if o.mask != nil && body != "" {
+		body = o.mask.ReplaceAllStringFunc(body, func(s string) string {
+			m := o.mask.FindStringSubmatch(s)
+			if m[1] != "" {
+				return m[1] + `"` + maskedValue + `"`
+			}
+			return m[3] + maskedValue
+		})
+	}
+	if truncated {
+		body += "..."
+	}
+	return body
+}
+
+func (o *loggerOptions) maskQuery(query string) string {
+	if len(o.maskFields) == 0 || query == "" {
+		return query
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return o.maskBody(query, false)
 	}
+	var masked bool
+	for k := range values {
+		if _, ok := o.maskFields[strings.ToLower(k)]; ok {
+			values[k] = []string{maskedValue}
+			masked = true
+		}
+	}
+	if !masked {
+		return query
+	}
+	return values.Encode()
+}
+
+// captureRequestBody reads the first limit bytes of the body, and restores the body for the next handlers.
+func captureRequestBody(c *gin.Context, limit int) (string, bool) {
+	body := c.Request.Body
+	if body == nil || body == http.NoBody || strings.HasPrefix(c.ContentType(), "multipart/") {
+		return "", false
+	}
+	buf, _ := io.ReadAll(io.LimitReader(body, int64(limit)+1))
+	c.Request.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(buf), body), Closer: body}
+	if len(buf) > limit {
+		return string(buf[:limit]), true
+	}
+	return string(buf), false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyWriter captures the first limit bytes of the response body.
+type bodyWriter struct {
+	gin.ResponseWriter
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) capture(b []byte) {
+	if n := w.limit - w.buf.Len(); n < len(b) {
+		b = b[:n]
+		w.truncated = true
+	}
+	w.buf.Write(b)
+}
+
+func requestIDFromGin(c *gin.Context) string {
+	if rid, ok := RequestIDFromContext(c.Request.Context()); ok {
+		return rid
+	}
+	return c.Writer.Header().Get(RequestIDHeader)
+}
+
+func traceIDFromContext(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
 }
 
-// GinRecovery recover掉项目可能出现的panic
+// GinRecovery recover掉项目可能出现的panic，以 kratos 的错误响应 500
 func GinRecovery(logger log.Logger, stack bool) gin.HandlerFunc {
+	return ginRecovery(logger, stack, kHttp.DefaultErrorEncoder)
+}
+
+// ginRecovery renders the panics by ene as recovery.ErrUnknownRequest.
+func ginRecovery(logger log.Logger, stack bool, ene kHttp.EncodeErrorFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
-			if err := recover(); err != nil {
-				// Check for a broken connection, as it is not really a
-				// condition that warrants a panic stack trace.
-				var brokenPipe bool
-				if ne, ok := err.(*net.OpError); ok {
-					if se, ok := ne.Err.(*os.SyscallError); ok {
-						if strings.Contains(strings.ToLower(se.Error()), "broken pipe") || strings.Contains(strings.ToLower(se.Error()), "connection reset by peer") {
-							brokenPipe = true
-						}
-					}
+			if rerr := recover(); rerr != nil {
+				err, ok := rerr.(error)
+				if !ok {
+					err = fmt.Errorf("%v", rerr)
 				}
-
 				httpRequest, _ := httputil.DumpRequest(c.Request, false)
-				if brokenPipe {
+				// Check for a broken connection, as it is not really a
+				// condition that warrants a panic stack trace.
+				if isBrokenPipe(err) {
 					_ = logger.Log(log.LevelError,
 						"path", c.Request.URL.Path,
 						"error", err,
 						"request", string(httpRequest),
 					)
 					// If the connection is dead, we can't write a status to it.
-					_ = c.Error(err.(error))
+					_ = c.Error(err)
 					c.Abort()
 					return
 				}
 
+				kvs := []any{
+					"msg", "[Recovery from panic]",
+					"error", err,
+					"request", string(httpRequest),
+					"request_id", requestIDFromGin(c),
+					"trace_id", traceIDFromContext(c.Request.Context()),
+				}
 				if stack {
-					_ = logger.Log(log.LevelError,
-						"[Recovery from panic]",
-						"error", err,
-						"request", string(httpRequest),
-						"stack", string(debug.Stack()),
-					)
-				} else {
-					_ = logger.Log(log.LevelError,
-						"[Recovery from panic]",
-						"error", err,
-						"request", string(httpRequest),
-					)
+					kvs = append(kvs, "stack", string(debug.Stack()))
 				}
-				c.AbortWithStatus(http.StatusInternalServerError)
+				_ = logger.Log(log.LevelError, kvs...)
+
+				_ = c.Error(err)
+				if !c.Writer.Written() {
+					ene(c.Writer, c.Request, recovery.ErrUnknownRequest)
+				}
+				c.Abort()
 			}
 		}()
 		c.Next()
 	}
 }
+
+func isBrokenPipe(err error) bool {
+	var ne *net.OpError
+	if !errors.As(err, &ne) {
+		return false
+	}
+	var se *os.SyscallError
+	if !errors.As(ne.Err, &se) {
+		return false
+	}
+	msg := strings.ToLower(se.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
@@ -0,0 +1,163 @@
+package gin
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+type logRecord struct {
+	level log.Level
+	kvs   map[string]any
+	keys  []string
+}
+
+type testLogger struct {
+	mu      sync.Mutex
+	records []logRecord
+}
+
+func (l *testLogger) Log(level log.Level, keyvals ...any) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r := logRecord{level: level, kvs: make(map[string]any)}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		k, _ := keyvals[i].(string)
+		r.kvs[k] = keyvals[i+1]
+		r.keys = append(r.keys, k)
+	}
+	l.records = append(l.records, r)
+	return nil
+}
+
+func (l *testLogger) last(t *testing.T) logRecord {
+	t.Helper()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.records) == 0 {
+		t.Fatal("expect a log record")
+	}
+	return l.records[len(l.records)-1]
+}
+
+func TestGinLogger(t *testing.T) {
+	logger := &testLogger{}
+	srv := NewServer(
+		WithRequestID(func() string { return "rid" }),
+		WithLogger(logger,
+			WithLogSkipPaths("/healthz"),
+			WithLogRequestBody(64),
+			WithLogResponseBody(16),
+			WithLogMaskFields("password", "token"),
+			WithLogSlowThreshold(50*time.Millisecond),
+		),
+	)
+	srv.GET("/healthz", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	srv.POST("/login", func(c *gin.Context) {
+		var in map[string]any
+		_ = c.ShouldBindJSON(&in)
+		c.JSON(http.StatusOK, in)
+	})
+	srv.GET("/slow", func(c *gin.Context) {
+		time.Sleep(60 * time.Millisecond)
+		c.String(http.StatusOK, "ok")
+	})
+	srv.GET("/fail", func(c *gin.Context) { c.String(http.StatusInternalServerError, "fail") })
+	logger.records = nil
+
+	serve(srv, http.MethodGet, "/healthz", "")
+	if len(logger.records) != 0 {
+		t.Errorf("expect the skipped path not logged, got %v", logger.records)
+	}
+
+	w := serve(srv, http.MethodPost, "/login?token=abc&page=1", `{"user":"alice","password":"secret"}`)
+	if !strings.Contains(w.Body.String(), `"password":"secret"`) {
+		t.Errorf("expect the handler reads the whole body, got %s", w.Body.String())
+	}
+	r := logger.last(t)
+	if r.level != log.LevelInfo || r.kvs["request_id"] != "rid" || r.kvs["route"] != "/login" {
+		t.Errorf("unexpected record %v", r.kvs)
+	}
+	if body := r.kvs["request_body"]; body != `{"user":"alice","password":"***"}` {
+		t.Errorf("unexpected request body %v", body)
+	}
+	if body := r.kvs["response_body"]; body != `{"password":"***"...` {
+		t.Errorf("unexpected response body %v", body)
+	}
+	if query := r.kvs["query"]; query != "page=1&token=%2A%2A%2A" {
+		t.Errorf("unexpected query %v", query)
+	}
+	var paths int
+	for _, k := range r.keys {
+		if k == "path" {
+			paths++
+		}
+	}
+	if paths != 1 {
+		t.Errorf("expect path logged once, got %d", paths)
+	}
+
+	serve(srv, http.MethodGet, "/slow", "")
+	if r = logger.last(t); r.level != log.LevelWarn || r.kvs["slow"] != true {
+		t.Errorf("expect the slow request logged at warn, got %v %v", r.level, r.kvs)
+	}
+	serve(srv, http.MethodGet, "/fail", "")
+	if r = logger.last(t); r.level != log.LevelError {
+		t.Errorf("expect the 5xx request logged at error, got %v", r.level)
+	}
+}
+
+func TestGinLogger_MaskForm(t *testing.T) {
+	logger := &testLogger{}
+	srv := NewServer()
+	srv.Use(GinLogger(logger, WithLogRequestBody(64), WithLogMaskFields("password")))
+	srv.POST("/login", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("user=alice&Password=secret"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	serveRequest(srv, req)
+	if body := logger.last(t).kvs["request_body"]; body != "user=alice&Password=***" {
+		t.Errorf("unexpected request body %v", body)
+	}
+}
+
+func TestGinRecovery(t *testing.T) {
+	logger := &testLogger{}
+	srv := NewServer(WithLogger(logger), WithResponseEncoder(ResponseEncoder), WithErrorEncoder(ErrorEncoder))
+	srv.GET("/panic", func(*gin.Context) { panic("boom") })
+	srv.GET("/broken", func(*gin.Context) {
+		panic(&net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)})
+	})
+	logger.records = nil
+
+	w := serve(srv, http.MethodGet, "/panic", "")
+	var resp Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusInternalServerError || resp.Reason != "UNKNOWN" {
+		t.Errorf("expect the panic rendered as a kratos error, got %d %s", w.Code, w.Body.String())
+	}
+	if r := logger.records[0]; r.kvs["error"] == nil || r.kvs["stack"] == nil {
+		t.Errorf("unexpected recovery record %v", r.kvs)
+	}
+
+	logger.records = nil
+	w = serve(srv, http.MethodGet, "/broken", "")
+	if w.Body.Len() != 0 {
+		t.Errorf("expect nothing written to the broken connection, got %s", w.Body.String())
+	}
+	if r := logger.records[0]; r.kvs["stack"] != nil {
+		t.Errorf("expect no stack for the broken connection, got %v", r.kvs)
+	}
+}
@@ -2,6 +2,8 @@ package gin
 
 import (
 	"crypto/tls"
+	"net"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -24,18 +26,63 @@ func WithTLSConfig(c *tls.Config) ServerOption {
 	}
 }
 
+// WithTLSCertFile 使用证书文件启用 TLS，tlsConf 中无需再配置证书
+func WithTLSCertFile(certFile, keyFile string) ServerOption {
+	return func(s *Server) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
 func WithAddress(addr string) ServerOption {
 	return func(s *Server) {
 		s.addr = addr
 	}
 }
 
+// WithListener 使用自定义的监听器，忽略 WithAddress
+func WithListener(lis net.Listener) ServerOption {
+	return func(s *Server) {
+		s.lis = lis
+	}
+}
+
+// WithTimeout 设置处理超时，超时后请求的 context 被取消，默认不超时。
+// 上传、长轮询和挂载的 WebSocket 等长时间的请求不宜设置
 func WithTimeout(timeout time.Duration) ServerOption {
 	return func(s *Server) {
 		s.timeout = timeout
 	}
 }
 
+// WithReadTimeout 设置读取整个请求（包括 body）的超时
+func WithReadTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.readTimeout = timeout
+	}
+}
+
+// WithWriteTimeout 设置写响应的超时
+func WithWriteTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.writeTimeout = timeout
+	}
+}
+
+// WithIdleTimeout 设置 keep-alive 连接的空闲超时
+func WithIdleTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.idleTimeout = timeout
+	}
+}
+
+// WithShutdownTimeout 设置 Stop 等待处理中请求的最长时间，超时后强制关闭连接
+func WithShutdownTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
 func WithMiddleware(m ...middleware.Middleware) ServerOption {
 	return func(s *Server) {
 		s.ms = m
@@ -72,12 +119,15 @@ func WithStrictSlash(strictSlash bool) ServerOption {
 	}
 }
 
-// WithLogger inject info logger
-func WithLogger(l log.Logger) ServerOption {
+// WithLogger inject info logger，opts 配置访问日志，panic 由 WithErrorEncoder 渲染
+func WithLogger(l log.Logger, opts ...LoggerOption) ServerOption {
 	return func(s *Server) {
 		gin.DefaultWriter = &infoLogger{Logger: l}
 		gin.DefaultErrorWriter = &errLogger{Logger: l}
-		s.Engine.Use(GinLogger(l), GinRecovery(l, true))
+		ene := func(w http.ResponseWriter, r *http.Request, err error) {
+			s.ene(w, r, err)
+		}
+		s.Engine.Use(GinLogger(l, opts...), ginRecovery(l, true, ene))
 	}
 }
 
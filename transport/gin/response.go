@@ -0,0 +1,202 @@
+package gin
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/encoding"
+	kjson "github.com/go-kratos/kratos/v2/encoding/json"
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// CodeSuccess 成功响应的 code
+	CodeSuccess = http.StatusOK
+	// MessageSuccess 成功响应的 message
+	MessageSuccess = "success"
+)
+
+// Response 统一响应结构，由 code、message 和 data 组成。
+// 错误响应的 code 为 kratos 错误的 code，并附带 reason 和 metadata（如参数校验失败的字段）。
+type Response struct {
+	Code     int64             `json:"code"`
+	Message  string            `json:"message"`
+	Reason   string            `json:"reason,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Data     json.RawMessage   `json:"data,omitempty"`
+}
+
+// Paging 是分页信息的取值接口，*PageInfo 和 utils/pageutil 的 *pageutil.PageInfo 都实现了它，
+// 已持有 pageutil.PageInfo 的调用方可直接传给 Page 和 NewPage
+type Paging interface {
+	GetTotal() int64
+	GetPageNum() int64
+	GetPageSize() int64
+	GetPages() int64
+}
+
+// PageInfo 分页信息，JSON 字段与 pageutil.PageInfo 一致。
+// 根模块不依赖 utils 模块（utils 依赖根模块，反向依赖会形成模块循环），因此在此定义而不直接引用 pageutil
+type PageInfo struct {
+	Total    int64 `json:"total,omitempty"`
+	PageNum  int64 `json:"page_num,omitempty"`
+	PageSize int64 `json:"page_size,omitempty"`
+	Pages    int64 `json:"pages,omitempty"`
+}
+
+// GetTotal 返回总条数
+func (p *PageInfo) GetTotal() int64 {
+	if p != nil {
+		return p.Total
+	}
+	return 0
+}
+
+// GetPageNum 返回页码
+func (p *PageInfo) GetPageNum() int64 {
+	if p != nil {
+		return p.PageNum
+	}
+	return 0
+}
+
+// GetPageSize 返回每页条数
+func (p *PageInfo) GetPageSize() int64 {
+	if p != nil {
+		return p.PageSize
+	}
+	return 0
+}
+
+// GetPages 返回总页数
+func (p *PageInfo) GetPages() int64 {
+	if p != nil {
+		return p.Pages
+	}
+	return 0
+}
+
+// PageData 分页响应的 data
+type PageData struct {
+	Info Paging
+	List any
+}
+
+// MarshalJSON 输出 total、page_num、page_size、pages 和 list
+func (p *PageData) MarshalJSON() ([]byte, error) {
+	list, err := marshalData(p.List)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []byte("[]")
+	}
+	info := &PageInfo{}
+	if p.Info != nil {
+		info = &PageInfo{
+			Total:    p.Info.GetTotal(),
+			PageNum:  p.Info.GetPageNum(),
+			PageSize: p.Info.GetPageSize(),
+			Pages:    p.Info.GetPages(),
+		}
+	}
+	return json.Marshal(struct {
+		*PageInfo
+		List json.RawMessage `json:"list"`
+	}{
+		PageInfo: info,
+		List:     list,
+	})
+}
+
+// NewPage 创建分页响应的 data，可作为处理函数的返回值交给 ResponseEncoder
+func NewPage(info Paging, list any) *PageData {
+	return &PageData{Info: info, List: list}
+}
+
+// FromError 将错误转换为 Response，code 为 kratos 错误的 code
+func FromError(err error) *Response {
+	if err == nil {
+		return &Response{Code: CodeSuccess, Message: MessageSuccess}
+	}
+	se := errors.FromError(err)
+	return &Response{Code: int64(se.Code), Message: se.Message, Reason: se.Reason, Metadata: se.Metadata}
+}
+
+// Success 以统一响应结构返回 data
+func Success(c *gin.Context, data any) {
+	if err := ResponseEncoder(c.Writer, c.Request, data); err != nil {
+		Fail(c, err)
+	}
+}
+
+// Fail 以统一响应结构返回错误，HTTP 状态码为 kratos 错误的 code
+func Fail(c *gin.Context, err error) {
+	_ = c.Error(err)
+	ErrorEncoder(c.Writer, c.Request, err)
+	c.Abort()
+}
+
+// Page 以统一响应结构返回分页数据
+func Page(c *gin.Context, info Paging, list any) {
+	Success(c, NewPage(info, list))
+}
+
+// ResponseEncoder 以统一响应结构编码响应，可用于 WithResponseEncoder
+func ResponseEncoder(w http.ResponseWriter, _ *http.Request, v any) error {
+	resp, ok := v.(*Response)
+	if !ok {
+		data, err := marshalData(v)
+		if err != nil {
+			return err
+		}
+		resp = &Response{Code: CodeSuccess, Message: MessageSuccess, Data: data}
+	}
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(body)
+	return err
+}
+
+// ErrorEncoder 以统一响应结构编码错误，可用于 WithErrorEncoder
+func ErrorEncoder(w http.ResponseWriter, _ *http.Request, err error) {
+	se := errors.FromError(err)
+	body, e := json.Marshal(FromError(se))
+	if e != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(se.Code))
+	_, _ = w.Write(body)
+}
+
+// marshalData 编码 data，proto 消息及其切片使用 kratos 的 json 编解码器，与默认编码器的输出一致
+func marshalData(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	codec := encoding.GetCodec(kjson.Name)
+	if _, ok := v.(proto.Message); ok {
+		return codec.Marshal(v)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || !rv.Type().Elem().Implements(reflect.TypeOf((*proto.Message)(nil)).Elem()) {
+		return json.Marshal(v)
+	}
+	list := make([]json.RawMessage, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		item, err := codec.Marshal(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	return json.Marshal(list)
+}
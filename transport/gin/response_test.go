@@ -0,0 +1,122 @@
+package gin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/errors"
+
+	api "github.com/jiushengTech/common/testing/api/protobuf"
+)
+
+func decodeResponse(t *testing.T, body []byte, data any) *Response {
+	t.Helper()
+	resp := new(Response)
+	if err := json.Unmarshal(body, resp); err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp
+}
+
+func TestResponse_Helpers(t *testing.T) {
+	srv := NewServer()
+	srv.GET("/success", func(c *gin.Context) {
+		Success(c, &api.Hygrothermograph{Humidity: "60"})
+	})
+	srv.GET("/page", func(c *gin.Context) {
+		Page(c, &PageInfo{Total: 3, PageNum: 2, PageSize: 2, Pages: 2}, []*api.Hygrothermograph{{Id: "3"}})
+	})
+	srv.GET("/fail", func(c *gin.Context) {
+		Fail(c, errors.NotFound("HYGROTHERMOGRAPH_NOT_FOUND", "hygrothermograph not found"))
+	})
+	srv.GET("/internal", func(c *gin.Context) {
+		Fail(c, fmt.Errorf("boom"))
+	})
+
+	w := serve(srv, http.MethodGet, "/success", "")
+	var out api.Hygrothermograph
+	resp := decodeResponse(t, w.Body.Bytes(), &out)
+	if w.Code != http.StatusOK || resp.Code != CodeSuccess || resp.Message != MessageSuccess || out.Humidity != "60" {
+		t.Errorf("unexpected success response %d %s", w.Code, w.Body.String())
+	}
+
+	w = serve(srv, http.MethodGet, "/page", "")
+	var page struct {
+		Total    int64                   `json:"total"`
+		PageNum  int64                   `json:"page_num"`
+		PageSize int64                   `json:"page_size"`
+		Pages    int64                   `json:"pages"`
+		List     []*api.Hygrothermograph `json:"list"`
+	}
+	decodeResponse(t, w.Body.Bytes(), &page)
+	if page.Total != 3 || page.PageNum != 2 || page.PageSize != 2 || page.Pages != 2 || len(page.List) != 1 || page.List[0].Id != "3" {
+		t.Errorf("unexpected page response %s", w.Body.String())
+	}
+
+	w = serve(srv, http.MethodGet, "/fail", "")
+	resp = decodeResponse(t, w.Body.Bytes(), nil)
+	if w.Code != http.StatusNotFound || resp.Code != http.StatusNotFound || resp.Reason != "HYGROTHERMOGRAPH_NOT_FOUND" || resp.Message != "hygrothermograph not found" {
+		t.Errorf("unexpected fail response %d %s", w.Code, w.Body.String())
+	}
+
+	w = serve(srv, http.MethodGet, "/internal", "")
+	if resp = decodeResponse(t, w.Body.Bytes(), nil); w.Code != http.StatusInternalServerError || resp.Code != http.StatusInternalServerError {
+		t.Errorf("unexpected internal error response %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestResponse_Encoders(t *testing.T) {
+	srv := NewServer(WithResponseEncoder(ResponseEncoder), WithErrorEncoder(ErrorEncoder))
+	srv.GET("/hygrothermograph/:id", srv.Handle(func(c *Context) error {
+		if c.Param("id") == "missing" {
+			return errors.NotFound("NOT_FOUND", "hygrothermograph not found")
+		}
+		return c.Result(http.StatusOK, NewPage(&PageInfo{Total: 1}, []*api.Hygrothermograph{{Id: c.Param("id")}}))
+	}))
+
+	w := serve(srv, http.MethodGet, "/hygrothermograph/1", "")
+	var page struct {
+		Total int64                   `json:"total"`
+		List  []*api.Hygrothermograph `json:"list"`
+	}
+	if resp := decodeResponse(t, w.Body.Bytes(), &page); resp.Code != CodeSuccess || page.Total != 1 || page.List[0].Id != "1" {
+		t.Errorf("unexpected response %s", w.Body.String())
+	}
+
+	w = serve(srv, http.MethodGet, "/hygrothermograph/missing", "")
+	if resp := decodeResponse(t, w.Body.Bytes(), nil); w.Code != http.StatusNotFound || resp.Reason != "NOT_FOUND" {
+		t.Errorf("unexpected error response %d %s", w.Code, w.Body.String())
+	}
+}
+
+// protoPageInfo has the getters of the generated pageutil.PageInfo.
+type protoPageInfo struct{ total, pageNum, pageSize, pages int64 }
+
+func (p *protoPageInfo) GetTotal() int64    { return p.total }
+func (p *protoPageInfo) GetPageNum() int64  { return p.pageNum }
+func (p *protoPageInfo) GetPageSize() int64 { return p.pageSize }
+func (p *protoPageInfo) GetPages() int64    { return p.pages }
+
+func TestPageData_Paging(t *testing.T) {
+	for _, tt := range []struct {
+		info Paging
+		want string
+	}{
+		{&protoPageInfo{total: 5, pageSize: 10, pages: 1}, `{"total":5,"page_size":10,"pages":1,"list":[]}`},
+		{(*PageInfo)(nil), `{"list":[]}`},
+		{nil, `{"list":[]}`},
+	} {
+		data, err := json.Marshal(NewPage(tt.info, nil))
+		if err != nil || string(data) != tt.want {
+			t.Errorf("expect %s, got %s, %v", tt.want, data, err)
+		}
+	}
+}
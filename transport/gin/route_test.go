@@ -0,0 +1,112 @@
+package gin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+
+	ginapi "github.com/jiushengTech/common/testing/api/gin"
+	api "github.com/jiushengTech/common/testing/api/protobuf"
+	"github.com/jiushengTech/common/transport/gin"
+)
+
+type hygrothermographService struct{}
+
+func (hygrothermographService) GetHygrothermograph(_ context.Context, in *api.GetHygrothermographRequest) (*api.Hygrothermograph, error) {
+	if in.Id == "missing" {
+		return nil, errors.NotFound("NOT_FOUND", "hygrothermograph not found")
+	}
+	return &api.Hygrothermograph{Id: in.Id, Unit: in.Unit, Humidity: "60", Temperature: "25"}, nil
+}
+
+func (hygrothermographService) UpdateHygrothermograph(_ context.Context, in *api.UpdateHygrothermographRequest) (*api.Hygrothermograph, error) {
+	out := &api.Hygrothermograph{Id: in.Id, Unit: in.Unit}
+	if in.Hygrothermograph != nil {
+		out.Humidity, out.Temperature = in.Hygrothermograph.Humidity, in.Hygrothermograph.Temperature
+	}
+	return out, nil
+}
+
+func TestRegisterGinServer(t *testing.T) {
+	var operations []string
+	operation := func(h middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				operations = append(operations, tr.Operation())
+			}
+			return h(ctx, req)
+		}
+	}
+	srv := gin.NewServer(gin.WithMiddleware(operation))
+	ginapi.RegisterHygrothermographServiceGinServer(srv, hygrothermographService{})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		code   int
+		want   *api.Hygrothermograph
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			target: "/hygrothermograph/1?unit=celsius",
+			code:   http.StatusOK,
+			want:   &api.Hygrothermograph{Id: "1", Unit: "celsius", Humidity: "60", Temperature: "25"},
+		},
+		{
+			name:   "notFound",
+			method: http.MethodGet,
+			target: "/hygrothermograph/missing",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "putBodyField",
+			method: http.MethodPut,
+			target: "/hygrothermograph/2?unit=kelvin",
+			body:   `{"humidity":"40","temperature":"300"}`,
+			code:   http.StatusOK,
+			want:   &api.Hygrothermograph{Id: "2", Unit: "kelvin", Humidity: "40", Temperature: "300"},
+		},
+		{
+			name:   "postBody",
+			method: http.MethodPost,
+			target: "/hygrothermograph/3",
+			body:   `{"unit":"celsius","hygrothermograph":{"humidity":"50"}}`,
+			code:   http.StatusOK,
+			want:   &api.Hygrothermograph{Id: "3", Unit: "celsius", Humidity: "50"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expect status %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			if tt.want == nil {
+				return
+			}
+			got := new(api.Hygrothermograph)
+			if err := json.Unmarshal(w.Body.Bytes(), got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Id != tt.want.Id || got.Unit != tt.want.Unit || got.Humidity != tt.want.Humidity || got.Temperature != tt.want.Temperature {
+				t.Errorf("expect %v, got %v", tt.want, got)
+			}
+		})
+	}
+	if len(operations) != len(tests) || operations[0] != ginapi.OperationHygrothermographServiceGetHygrothermograph {
+		t.Errorf("unexpected operations %v", operations)
+	}
+}
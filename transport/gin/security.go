@@ -0,0 +1,369 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/errors"
+	authjwt "github.com/go-kratos/kratos/v2/middleware/auth/jwt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+
+	"github.com/jiushengTech/common/id"
+	"github.com/jiushengTech/common/transport/internal/origin"
+)
+
+// RequestIDHeader 请求 ID 的请求头和响应头
+const RequestIDHeader = "X-Request-ID"
+
+var (
+	// ErrBodyTooLarge 请求体超过 WithBodyLimit 的限制
+	ErrBodyTooLarge = errors.New(http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE", "request body too large")
+	// ErrLimitExceed 请求超过 WithRateLimit 的限制
+	ErrLimitExceed = errors.New(http.StatusTooManyRequests, "RATELIMIT", "service unavailable due to rate limit exceeded")
+	// ErrOriginNotAllowed 跨域预检请求的来源不在 CORSConfig.AllowOrigins 中
+	ErrOriginNotAllowed = errors.Forbidden("CORS", "origin not allowed")
+)
+
+// CORSConfig 跨域配置
+type CORSConfig struct {
+	// AllowOrigins 允许的来源，"*" 允许所有来源，"https://*.example.com" 允许子域名
+	AllowOrigins []string
+	// AllowMethods 允许的方法，为空时为 GET、POST、PUT、PATCH、DELETE、HEAD
+	AllowMethods []string
+	// AllowHeaders 允许的请求头，为空时回显预检请求的 Access-Control-Request-Headers
+	AllowHeaders []string
+	// ExposeHeaders 允许浏览器读取的响应头
+	ExposeHeaders []string
+	// AllowCredentials 允许携带 cookie 等凭证，此时 "*" 回显请求的来源
+	AllowCredentials bool
+	// MaxAge 预检请求结果的缓存时间
+	MaxAge time.Duration
+}
+
+// SecureHeadersConfig 安全响应头配置，为空的字段不设置对应的响应头
+type SecureHeadersConfig struct {
+	// HSTSMaxAge Strict-Transport-Security 的 max-age，只在 https 请求中设置
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	// ContentSecurityPolicy Content-Security-Policy
+	ContentSecurityPolicy string
+	// FrameOptions X-Frame-Options，如 DENY、SAMEORIGIN
+	FrameOptions string
+	// ContentTypeNosniff 设置 X-Content-Type-Options: nosniff
+	ContentTypeNosniff bool
+	// ReferrerPolicy Referrer-Policy
+	ReferrerPolicy string
+}
+
+// DefaultSecureHeaders 默认的安全响应头配置
+func DefaultSecureHeaders() SecureHeadersConfig {
+	return SecureHeadersConfig{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentSecurityPolicy: "default-src 'self'",
+		FrameOptions:          "DENY",
+		ContentTypeNosniff:    true,
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+	}
+}
+
+// JWTConfig JWT 认证配置，认证通过后 claims 可通过 kratos 的 jwt.FromContext 获取
+type JWTConfig struct {
+	// KeyFunc 返回校验签名的密钥
+	KeyFunc jwt.Keyfunc
+	// SigningMethod 签名算法，默认 HS256
+	SigningMethod jwt.SigningMethod
+	// Claims 返回解析 claims 的结构，默认 jwt.MapClaims
+	Claims func() jwt.Claims
+	// SkipPaths 不需要认证的路由，与路由模板（如 /user/:id）或请求路径比较
+	SkipPaths []string
+}
+
+// KeyFunc 返回限流的 key，返回空字符串的请求不限流
+type KeyFunc func(c *gin.Context) string
+
+// KeyByIP 按客户端 IP 限流
+func KeyByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// KeyByHeader 按请求头限流，如 API Key
+func KeyByHeader(name string) KeyFunc {
+	return func(c *gin.Context) string {
+		return c.GetHeader(name)
+	}
+}
+
+// UUIDRequestID 使用 UUID 作为请求 ID
+func UUIDRequestID() string {
+	return uuid.NewString()
+}
+
+// SnowflakeRequestID 使用雪花算法生成请求 ID
+func SnowflakeRequestID(sf *id.Snowflake) func() string {
+	return sf.String
+}
+
+type requestIDKey struct{}
+
+// RequestIDFromContext 获取 WithRequestID 设置的请求 ID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	rid, ok := ctx.Value(requestIDKey{}).(string)
+	return rid, ok
+}
+
+// WithCORS 处理跨域请求，预检请求直接返回
+func WithCORS(cfg CORSConfig) ServerOption {
+	return func(s *Server) {
+		s.Engine.Use(s.cors(cfg))
+	}
+}
+
+// WithBodyLimit 限制请求体的大小，超过时返回 ErrBodyTooLarge
+func WithBodyLimit(limit int64) ServerOption {
+	return func(s *Server) {
+		s.Engine.Use(s.bodyLimit(limit))
+	}
+}
+
+// WithRateLimit 按 key 使用令牌桶限流，每秒生成 r 个令牌，桶容量为 burst，超过时返回 ErrLimitExceed
+func WithRateLimit(r rate.Limit, burst int, key KeyFunc) ServerOption {
+	return func(s *Server) {
+		s.Engine.Use(s.rateLimit(r, burst, key))
+	}
+}
+
+// WithSecureHeaders 设置安全响应头
+func WithSecureHeaders(cfg SecureHeadersConfig) ServerOption {
+	return func(s *Server) {
+		s.Engine.Use(secureHeaders(cfg))
+	}
+}
+
+// WithRequestID 沿用请求头中的请求 ID 或使用 gen 生成，gen 为空时使用 UUID。
+// 请求 ID 写入请求头、响应头和请求的 context
+func WithRequestID(gen func() string) ServerOption {
+	return func(s *Server) {
+		if gen == nil {
+			gen = UUIDRequestID
+		}
+		s.Engine.Use(requestID(gen))
+	}
+}
+
+// WithJWT 校验 Authorization: Bearer <token>，失败时返回 kratos jwt 中间件的错误
+func WithJWT(cfg JWTConfig) ServerOption {
+	return func(s *Server) {
+		s.Engine.Use(s.jwt(cfg))
+	}
+}
+
+func (s *Server) cors(cfg CORSConfig) gin.HandlerFunc {
+	methods := cfg.AllowMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead}
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(cfg.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge / time.Second))
+	return func(c *gin.Context) {
+		reqOrigin := c.GetHeader("Origin")
+		if reqOrigin == "" {
+			c.Next()
+			return
+		}
+		h := c.Writer.Header()
+		h.Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		allowed, wildcard := origin.Match(cfg.AllowOrigins, reqOrigin)
+		if !allowed {
+			if preflight {
+				s.abort(c, ErrOriginNotAllowed)
+				return
+			}
+			c.Next()
+			return
+		}
+		if wildcard && !cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", reqOrigin)
+		}
+		if cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if !preflight {
+			if exposeHeaders != "" {
+				h.Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			c.Next()
+			return
+		}
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", allowMethods)
+		if allowHeaders != "" {
+			h.Set("Access-Control-Allow-Headers", allowHeaders)
+		} else if reqHeaders := c.GetHeader("Access-Control-Request-Headers"); reqHeaders != "" {
+			h.Set("Access-Control-Allow-Headers", reqHeaders)
+		}
+		if cfg.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+func (s *Server) bodyLimit(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			s.abort(c, ErrBodyTooLarge)
+			return
+		}
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		c.Next()
+	}
+}
+
+type visitor struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func (s *Server) rateLimit(r rate.Limit, burst int, key KeyFunc) gin.HandlerFunc {
+	var (
+		mu        sync.Mutex
+		visitors  = make(map[string]*visitor)
+		lastSweep = time.Now()
+	)
+	// idle buckets refill completely in burst/r, so they can be dropped after it.
+	idle := time.Minute
+	if r > 0 {
+		if d := time.Duration(float64(burst) / float64(r) * float64(time.Second)); d > idle {
+			idle = d
+		}
+	}
+	allow := func(k string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		now := time.Now()
+		if now.Sub(lastSweep) > idle {
+			for k, v := range visitors {
+				if now.Sub(v.lastSeen) > idle {
+					delete(visitors, k)
+				}
+			}
+			lastSweep = now
+		}
+		v, ok := visitors[k]
+		if !ok {
+			v = &visitor{limiter: rate.NewLimiter(r, burst)}
+			visitors[k] = v
+		}
+		v.lastSeen = now
+		return v.limiter.AllowN(now, 1)
+	}
+	return func(c *gin.Context) {
+		if k := key(c); k != "" && !allow(k) {
+			s.abort(c, ErrLimitExceed)
+			return
+		}
+		c.Next()
+	}
+}
+
+func secureHeaders(cfg SecureHeadersConfig) gin.HandlerFunc {
+	var hsts string
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge/time.Second))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		if hsts != "" && (c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")) {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		if cfg.ContentSecurityPolicy != "" {
+			h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ContentTypeNosniff {
+			h.Set("X-Content-Type-Options", "nosniff")
+		}
+		if cfg.ReferrerPolicy != "" {
+			h.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		c.Next()
+	}
+}
+
+func requestID(gen func() string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetHeader(RequestIDHeader)
+		if !validRequestID(rid) {
+			rid = gen()
+			c.Request.Header.Set(RequestIDHeader, rid)
+		}
+		c.Writer.Header().Set(RequestIDHeader, rid)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDKey{}, rid))
+		c.Next()
+	}
+}
+
+// validRequestID reports whether the request ID from the client can be propagated,
+// it must be short and printable ASCII to be safe in the headers and logs.
+func validRequestID(rid string) bool {
+	if rid == "" || len(rid) > 128 {
+		return false
+	}
+	for i := 0; i < len(rid); i++ {
+		if rid[i] < 0x21 || rid[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) jwt(cfg JWTConfig) gin.HandlerFunc {
+	var opts []authjwt.Option
+	if cfg.SigningMethod != nil {
+		opts = append(opts, authjwt.WithSigningMethod(cfg.SigningMethod))
+	}
+	if cfg.Claims != nil {
+		opts = append(opts, authjwt.WithClaims(cfg.Claims))
+	}
+	auth := s.Middleware(authjwt.Server(cfg.KeyFunc, opts...))
+	skip := make(map[string]struct{}, len(cfg.SkipPaths))
+	for _, p := range cfg.SkipPaths {
+		skip[p] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		if _, ok := skip[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+		auth(c)
+	}
+}
@@ -0,0 +1,197 @@
+package gin
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authjwt "github.com/go-kratos/kratos/v2/middleware/auth/jwt"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func serveRequest(srv *Server, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	return w
+}
+
+func TestSecurity_CORS(t *testing.T) {
+	srv := NewServer(WithCORS(CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com", "https://*.example.org"},
+		AllowHeaders:     []string{"Authorization", "Content-Type"},
+		ExposeHeaders:    []string{RequestIDHeader},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}))
+	srv.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	tests := []struct {
+		name   string
+		method string
+		origin string
+		code   int
+		allow  string
+	}{
+		{name: "preflight", method: http.MethodOptions, origin: "https://app.example.com", code: http.StatusNoContent, allow: "https://app.example.com"},
+		{name: "subdomain", method: http.MethodOptions, origin: "https://a.example.org", code: http.StatusNoContent, allow: "https://a.example.org"},
+		{name: "preflight denied", method: http.MethodOptions, origin: "https://evil.com", code: http.StatusForbidden},
+		{name: "simple", method: http.MethodGet, origin: "https://app.example.com", code: http.StatusOK, allow: "https://app.example.com"},
+		{name: "simple denied", method: http.MethodGet, origin: "https://example.org", code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/ping", nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+			w := serveRequest(srv, req)
+			if w.Code != tt.code || w.Header().Get("Access-Control-Allow-Origin") != tt.allow {
+				t.Errorf("expect %d %q, got %d %q", tt.code, tt.allow, w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+			}
+			if tt.code == http.StatusNoContent && (w.Header().Get("Access-Control-Max-Age") != "3600" || w.Header().Get("Access-Control-Allow-Credentials") != "true") {
+				t.Errorf("unexpected preflight headers %v", w.Header())
+			}
+		})
+	}
+}
+
+func TestSecurity_BodyLimit(t *testing.T) {
+	srv := NewServer(WithBodyLimit(8))
+	srv.POST("/echo", srv.Handle(func(c *Context) error {
+		b, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return ErrBodyTooLarge
+		}
+		return c.Result(http.StatusOK, string(b))
+	}))
+
+	if w := serve(srv, http.MethodPost, "/echo", `"1234"`); w.Code != http.StatusOK {
+		t.Errorf("expect 200, got %d", w.Code)
+	}
+	if w := serve(srv, http.MethodPost, "/echo", `"123456789"`); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expect 413, got %d", w.Code)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`"123456789"`))
+	req.ContentLength = -1
+	if w := serveRequest(srv, req); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expect 413 for the chunked body, got %d", w.Code)
+	}
+}
+
+func TestSecurity_RateLimit(t *testing.T) {
+	srv := NewServer(WithRateLimit(1, 2, KeyByHeader("X-API-Key")))
+	srv.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	get := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("X-API-Key", key)
+		return serveRequest(srv, req).Code
+	}
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if code := get("a"); code != want {
+			t.Errorf("request %d: expect %d, got %d", i, want, code)
+		}
+	}
+	if code := get("b"); code != http.StatusOK {
+		t.Errorf("expect another key not limited, got %d", code)
+	}
+	for i := 0; i < 3; i++ {
+		if code := get(""); code != http.StatusOK {
+			t.Errorf("expect the empty key not limited, got %d", code)
+		}
+	}
+}
+
+func TestSecurity_SecureHeaders(t *testing.T) {
+	srv := NewServer(WithSecureHeaders(DefaultSecureHeaders()))
+	srv.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	w := serve(srv, http.MethodGet, "/ping", "")
+	if w.Header().Get("X-Frame-Options") != "DENY" || w.Header().Get("Content-Security-Policy") == "" || w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("unexpected headers %v", w.Header())
+	}
+	if w.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("expect no HSTS over http")
+	}
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	if hsts := serveRequest(srv, req).Header().Get("Strict-Transport-Security"); hsts != "max-age=31536000; includeSubDomains" {
+		t.Errorf("unexpected HSTS %q", hsts)
+	}
+}
+
+func TestSecurity_RequestID(t *testing.T) {
+	srv := NewServer(WithRequestID(func() string { return "generated" }))
+	srv.GET("/ping", func(c *gin.Context) {
+		rid, _ := RequestIDFromContext(c)
+		c.String(http.StatusOK, rid)
+	})
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "generate", in: "", want: "generated"},
+		{name: "propagate", in: "abc-123", want: "abc-123"},
+		{name: "invalid", in: "bad id\n", want: "generated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.Header.Set(RequestIDHeader, tt.in)
+			w := serveRequest(srv, req)
+			if w.Body.String() != tt.want || w.Header().Get(RequestIDHeader) != tt.want {
+				t.Errorf("expect %q, got %q %q", tt.want, w.Body.String(), w.Header().Get(RequestIDHeader))
+			}
+		})
+	}
+}
+
+func TestSecurity_JWT(t *testing.T) {
+	key := []byte("secret")
+	srv := NewServer(WithJWT(JWTConfig{
+		KeyFunc:   func(*jwt.Token) (any, error) { return key, nil },
+		SkipPaths: []string{"/login"},
+	}))
+	srv.GET("/login", func(c *gin.Context) { c.String(http.StatusOK, "login") })
+	srv.GET("/user/:id", func(c *gin.Context) {
+		claims, _ := authjwt.FromContext(c)
+		sub, _ := claims.GetSubject()
+		c.String(http.StatusOK, sub)
+	})
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "alice"}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		code   int
+		body   string
+	}{
+		{name: "skip", path: "/login", code: http.StatusOK, body: "login"},
+		{name: "missing", path: "/user/1", code: http.StatusUnauthorized},
+		{name: "invalid", path: "/user/1", header: "Bearer invalid", code: http.StatusUnauthorized},
+		{name: "valid", path: "/user/1", header: "Bearer " + token, code: http.StatusOK, body: "alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := serveRequest(srv, req)
+			if w.Code != tt.code || (tt.body != "" && w.Body.String() != tt.body) {
+				t.Errorf("expect %d %q, got %d %q", tt.code, tt.body, w.Code, w.Body.String())
+			}
+		})
+	}
+}
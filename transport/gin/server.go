@@ -3,10 +3,12 @@ package gin
 import (
 	"context"
 	"crypto/tls"
-	"github.com/go-kratos/kratos/v2/errors"
+	"errors"
+	"net"
 	"net/http"
 	"net/url"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +17,8 @@ import (
 	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/transport"
 	kHttp "github.com/go-kratos/kratos/v2/transport/http"
+
+	"github.com/jiushengTech/common/transport/internal/host"
 )
 
 var (
@@ -26,12 +30,27 @@ type Server struct {
 	*gin.Engine
 	server *http.Server
 
-	tlsConf *tls.Config
-	timeout time.Duration
-	addr    string
+	tlsConf  *tls.Config
+	certFile string
+	keyFile  string
+	timeout  time.Duration
+	network  string
+	addr     string
+	lis      net.Listener
+	endpoint *url.URL
+
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	shutdownTimeout time.Duration
 
 	err error
 
+	checkers      []HealthChecker
+	checkersMu    sync.RWMutex
+	healthTimeout time.Duration
+	stopping      atomic.Bool
+
 	filters []kHttp.FilterFunc
 	ms      []middleware.Middleware
 	dec     kHttp.DecodeRequestFunc
@@ -41,10 +60,13 @@ type Server struct {
 
 func NewServer(opts ...ServerOption) *Server {
 	srv := &Server{
-		timeout: 1 * time.Second,
+		network: "tcp",
+		addr:    ":0",
 		dec:     kHttp.DefaultRequestDecoder,
 		enc:     kHttp.DefaultResponseEncoder,
 		ene:     kHttp.DefaultErrorEncoder,
+
+		healthTimeout: 3 * time.Second,
 	}
 
 	srv.init(opts...)
@@ -54,47 +76,49 @@ func NewServer(opts ...ServerOption) *Server {
 
 func (s *Server) init(opts ...ServerOption) {
 	s.Engine = gin.New()
+	// gin.Context delegates Deadline, Done, Err and Value to the request context,
+	// so it can be passed to kratos middlewares as context.Context
+	s.Engine.ContextWithFallback = true
+	s.Engine.Use(s.transport())
 
 	for _, o := range opts {
 		o(s)
 	}
 
 	s.server = &http.Server{
-		Addr:      s.addr,
-		Handler:   s.Engine,
-		TLSConfig: s.tlsConf,
+		Addr:         s.addr,
+		Handler:      kHttp.FilterChain(s.filters...)(s.Engine),
+		TLSConfig:    s.tlsConf,
+		ReadTimeout:  s.readTimeout,
+		WriteTimeout: s.writeTimeout,
+		IdleTimeout:  s.idleTimeout,
 	}
 }
 
+// Endpoint return a real address to registry endpoint, e.g. http://192.168.1.10:8000.
+// The server listens on the address first, so the port of :0 and the real IP are reported.
 func (s *Server) Endpoint() (*url.URL, error) {
-	addr := s.addr
-
-	prefix := "http://"
-	if s.tlsConf == nil {
-		if !strings.HasPrefix(addr, "http://") {
-			prefix = "http://"
-		}
-	} else {
-		if !strings.HasPrefix(addr, "https://") {
-			prefix = "https://"
-		}
+	if err := s.listenAndEndpoint(); err != nil {
+		return nil, err
 	}
-	addr = prefix + addr
-
-	var endpoint *url.URL
-	endpoint, s.err = url.Parse(addr)
-
-	return endpoint, s.err
+	return s.endpoint, nil
 }
 
+// Start start the HTTP server, serving until Stop is called.
 func (s *Server) Start(ctx context.Context) error {
-	log.Infof("[GIN] server listening on: %s", s.addr)
+	if err := s.listenAndEndpoint(); err != nil {
+		return err
+	}
+	s.server.BaseContext = func(net.Listener) context.Context {
+		return ctx
+	}
+	log.Infof("[GIN] server listening on: %s", s.lis.Addr().String())
 
 	var err error
-	if s.tlsConf != nil {
-		err = s.server.ListenAndServeTLS("", "")
+	if s.tlsConf != nil || s.certFile != "" {
+		err = s.server.ServeTLS(s.lis, s.certFile, s.keyFile)
 	} else {
-		err = s.server.ListenAndServe()
+		err = s.server.Serve(s.lis)
 	}
 	if !errors.Is(err, http.ErrServerClosed) {
 		log.Error(err)
@@ -103,11 +127,52 @@ func (s *Server) Start(ctx context.Context) error {
 	return nil
 }
 
+// Stop stop the HTTP server, waiting for the in-flight requests until ctx is done or the shutdown timeout.
+// The connections are closed by force if the requests are not finished in time.
 func (s *Server) Stop(ctx context.Context) error {
 	log.Info("[GIN] server stopping")
-	return s.server.Shutdown(ctx)
+	s.stopping.Store(true)
+	if s.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.shutdownTimeout)
+		defer cancel()
+	}
+	err := s.server.Shutdown(ctx)
+	if err != nil && ctx.Err() != nil {
+		log.Warn("[GIN] server couldn't stop gracefully in time, doing force stop")
+		err = s.server.Close()
+	}
+	if s.lis != nil {
+		// opened by Endpoint but never served, Shutdown only closes the served listeners
+		_ = s.lis.Close()
+	}
+	return err
 }
 
 func (s *Server) ServeHTTP(res http.ResponseWriter, req *http.Request) {
-	s.Engine.ServeHTTP(res, req)
+	s.server.Handler.ServeHTTP(res, req)
+}
+
+func (s *Server) listenAndEndpoint() error {
+	if s.lis == nil {
+		lis, err := net.Listen(s.network, s.addr)
+		if err != nil {
+			s.err = err
+			return err
+		}
+		s.lis = lis
+	}
+	if s.endpoint == nil {
+		addr, err := host.Extract(s.addr, s.lis)
+		if err != nil {
+			s.err = err
+			return err
+		}
+		scheme := "http"
+		if s.tlsConf != nil || s.certFile != "" {
+			scheme = "https"
+		}
+		s.endpoint = &url.URL{Scheme: scheme, Host: addr}
+	}
+	return s.err
 }
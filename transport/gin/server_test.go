@@ -3,8 +3,11 @@ package gin
 import (
 	"context"
 	"math/rand"
+	"net"
+	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	kHttp "github.com/go-kratos/kratos/v2/transport/http"
@@ -15,11 +18,26 @@ import (
 	api "github.com/jiushengTech/common/testing/api/protobuf"
 )
 
+// start starts srv in background, and returns its endpoint host.
+func start(t *testing.T, srv *Server) string {
+	t.Helper()
+	endpoint, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		if err := srv.Start(context.Background()); err != nil {
+			t.Errorf("expected nil got %v", err)
+		}
+	}()
+	return endpoint.Host
+}
+
 func TestServer(t *testing.T) {
 	ctx := context.Background()
 
 	srv := NewServer(
-		WithAddress(":8800"),
+		WithAddress("127.0.0.1:0"),
 	)
 
 	srv.Use(gin.Recovery())
@@ -40,28 +58,22 @@ func TestServer(t *testing.T) {
 		c.JSON(200, &out)
 	})
 
-	if err := srv.Start(ctx); err != nil {
-		panic(err)
-	}
-
+	addr := start(t, srv)
 	defer func() {
 		if err := srv.Stop(ctx); err != nil {
 			t.Errorf("expected nil got %v", err)
 		}
 	}()
-}
-
-func TestClient(t *testing.T) {
-	ctx := context.Background()
 
 	cli, err := kHttp.NewClient(ctx,
-		kHttp.WithEndpoint("127.0.0.1:8800"),
+		kHttp.WithEndpoint(addr),
 	)
 	assert.Nil(t, err)
 	assert.NotNil(t, cli)
 
 	resp, err := GetHygrothermograph(ctx, cli, nil, kHttp.EmptyCallOption{})
 	assert.Nil(t, err)
+	assert.NotEmpty(t, resp.GetHumidity())
 	t.Log(resp)
 }
 
@@ -81,3 +93,103 @@ func GetHygrothermograph(ctx context.Context, cli *kHttp.Client, in *api.Hygroth
 
 	return &out, nil
 }
+
+func TestServer_Endpoint(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(WithListener(lis))
+	endpoint, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(lis.Addr().String())
+	if endpoint.Scheme != "http" || endpoint.Port() != port || endpoint.Hostname() == "" {
+		t.Errorf("unexpected endpoint %s, listening on %s", endpoint, lis.Addr())
+	}
+	_ = lis.Close()
+
+	srv = NewServer(WithAddress("127.0.0.1:0"), WithTLSCertFile("cert.pem", "key.pem"))
+	if endpoint, err = srv.Endpoint(); err != nil || endpoint.Scheme != "https" || endpoint.Port() == "0" {
+		t.Errorf("unexpected endpoint %v, %v", endpoint, err)
+	}
+	_ = srv.lis.Close()
+}
+
+func TestServer_Timeout(t *testing.T) {
+	srv := NewServer(WithTimeout(50 * time.Millisecond))
+	srv.GET("/slow", func(c *gin.Context) {
+		select {
+		case <-c.Done():
+			c.String(http.StatusGatewayTimeout, c.Err().Error())
+		case <-time.After(time.Second):
+			c.String(http.StatusOK, "ok")
+		}
+	})
+	if w := serve(srv, http.MethodGet, "/slow", ""); w.Code != http.StatusGatewayTimeout {
+		t.Errorf("expect the handler timeout, got %d", w.Code)
+	}
+
+	// no timeout by default
+	srv = NewServer()
+	srv.GET("/deadline", func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.String(http.StatusOK, "%v", ok)
+	})
+	if w := serve(srv, http.MethodGet, "/deadline", ""); w.Body.String() != "false" {
+		t.Errorf("expect no deadline by default, got %s", w.Body.String())
+	}
+}
+
+func TestServer_StopWithoutStart(t *testing.T) {
+	srv := NewServer(WithAddress("127.0.0.1:0"))
+	endpoint, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = srv.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if conn, err := net.Dial("tcp", endpoint.Host); err == nil {
+		_ = conn.Close()
+		t.Error("expect the pre-opened listener closed")
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		shutdown time.Duration
+		ok       bool
+	}{
+		{name: "drain", shutdown: time.Second, ok: true},
+		{name: "deadline", shutdown: 50 * time.Millisecond, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{})
+			srv := NewServer(WithAddress("127.0.0.1:0"), WithTimeout(0), WithShutdownTimeout(tt.shutdown))
+			srv.GET("/slow", func(c *gin.Context) {
+				close(started)
+				time.Sleep(300 * time.Millisecond)
+				c.String(http.StatusOK, "ok")
+			})
+			addr := start(t, srv)
+
+			done := make(chan error, 1)
+			go func() {
+				resp, err := http.Get("http://" + addr + "/slow")
+				if err == nil {
+					_ = resp.Body.Close()
+				}
+				done <- err
+			}()
+			<-started
+			_ = srv.Stop(context.Background())
+			if err := <-done; (err == nil) != tt.ok {
+				t.Errorf("expect the in-flight request ok=%v, got %v", tt.ok, err)
+			}
+		})
+	}
+}
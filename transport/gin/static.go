@@ -0,0 +1,207 @@
+package gin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// StaticOption WithStatic 的配置
+type StaticOption func(*staticOptions)
+
+type staticOptions struct {
+	maxAge time.Duration
+	index  string
+	spa    bool
+}
+
+// WithStaticMaxAge 设置 Cache-Control 的 max-age，默认为 no-cache，即每次通过 ETag 校验。
+// 入口 html 始终为 no-cache
+func WithStaticMaxAge(maxAge time.Duration) StaticOption {
+	return func(o *staticOptions) {
+		o.maxAge = maxAge
+	}
+}
+
+// WithStaticIndex 设置目录的默认文件，默认为 index.html
+func WithStaticIndex(index string) StaticOption {
+	return func(o *staticOptions) {
+		o.index = index
+	}
+}
+
+// WithSPA 单页应用，找不到的 html 请求返回入口 html，由前端路由处理
+func WithSPA() StaticOption {
+	return func(o *staticOptions) {
+		o.spa = true
+	}
+}
+
+// WithStatic 在 prefix 下提供 fsys 中的文件，支持 ETag 和预压缩的 .br、.gz 文件。
+// prefix 为 / 时作为 NoRoute 处理，避免与 API 路由冲突
+func WithStatic(prefix string, fsys fs.FS, opts ...StaticOption) ServerOption {
+	return func(s *Server) {
+		o := &staticOptions{index: "index.html"}
+		for _, opt := range opts {
+			opt(o)
+		}
+		h := s.static(fsys, o)
+		prefix = strings.TrimRight(prefix, "/")
+		if prefix == "" {
+			s.Engine.NoRoute(h)
+			return
+		}
+		s.Engine.GET(prefix+"/*filepath", h)
+		s.Engine.HEAD(prefix+"/*filepath", h)
+	}
+}
+
+type staticFile struct {
+	name     string
+	encoding string
+	modTime  time.Time
+	content  io.ReadSeeker
+	closer   io.Closer
+}
+
+func (s *Server) static(fsys fs.FS, o *staticOptions) gin.HandlerFunc {
+	var etags sync.Map
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			s.abort(c, errors.NotFound("NOT_FOUND", "not found"))
+			return
+		}
+		name := c.Param("filepath")
+		if name == "" {
+			name = c.Request.URL.Path
+		}
+		name = strings.TrimPrefix(path.Clean("/"+name), "/")
+		if name == "" || strings.HasSuffix(c.Request.URL.Path, "/") {
+			name = path.Join(name, o.index)
+		}
+
+		f, err := openStatic(fsys, name, c.GetHeader("Accept-Encoding"))
+		if err != nil && o.spa && path.Ext(name) == "" && strings.Contains(c.GetHeader("Accept"), "text/html") {
+			name = o.index
+			f, err = openStatic(fsys, name, c.GetHeader("Accept-Encoding"))
+		}
+		if err != nil {
+			s.abort(c, errors.NotFound("NOT_FOUND", "file not found"))
+			return
+		}
+		defer f.closer.Close()
+
+		h := c.Writer.Header()
+		if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+			h.Set("Content-Type", ctype)
+		} else if f.encoding != "" {
+			// don't sniff the type of the compressed content
+			h.Set("Content-Type", "application/octet-stream")
+		}
+		if f.encoding != "" {
+			h.Set("Content-Encoding", f.encoding)
+		}
+		h.Add("Vary", "Accept-Encoding")
+		if o.maxAge > 0 && path.Base(name) != o.index {
+			h.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(o.maxAge/time.Second)))
+		} else {
+			h.Set("Cache-Control", "no-cache")
+		}
+		etag, err := staticETag(&etags, f)
+		if err != nil {
+			s.abort(c, errors.InternalServer("STATIC", err.Error()))
+			return
+		}
+		h.Set("ETag", etag)
+		http.ServeContent(c.Writer, c.Request, name, f.modTime, f.content)
+	}
+}
+
+// openStatic opens the precompressed variant of name accepted by the client, or name itself.
+func openStatic(fsys fs.FS, name, acceptEncoding string) (*staticFile, error) {
+	if !fs.ValidPath(name) {
+		return nil, fs.ErrNotExist
+	}
+	for _, enc := range []struct{ encoding, ext string }{{"br", ".br"}, {"gzip", ".gz"}} {
+		if !acceptsEncoding(acceptEncoding, enc.encoding) {
+			continue
+		}
+		if f, err := openStaticFile(fsys, name+enc.ext); err == nil {
+			f.name, f.encoding = name, enc.encoding
+			return f, nil
+		}
+	}
+	return openStaticFile(fsys, name)
+}
+
+func openStaticFile(fsys fs.FS, name string) (*staticFile, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		_ = f.Close()
+		return nil, fs.ErrNotExist
+	}
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		rs = bytes.NewReader(b)
+	}
+	return &staticFile{name: name, modTime: info.ModTime(), content: rs, closer: f}, nil
+}
+
+func acceptsEncoding(acceptEncoding, encoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		enc, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(enc), encoding) {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// staticETag returns the content hash of f, cached by its name, encoding, size and modification time.
+func staticETag(etags *sync.Map, f *staticFile) (string, error) {
+	size, err := f.content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s|%s|%d|%d", f.name, f.encoding, size, f.modTime.UnixNano())
+	if etag, ok := etags.Load(key); ok {
+		_, err = f.content.Seek(0, io.SeekStart)
+		return etag.(string), err
+	}
+	if _, err = f.content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	if _, err = io.Copy(hash, f.content); err != nil {
+		return "", err
+	}
+	if _, err = f.content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	etags.Store(key, etag)
+	return etag, nil
+}
@@ -0,0 +1,81 @@
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServer_Static(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":       {Data: []byte("<html>index</html>")},
+		"assets/app.js":    {Data: []byte("console.log('app')")},
+		"assets/app.css":   {Data: []byte("body{}")},
+		"assets/app.js.br": {Data: []byte("br")},
+		"assets/app.js.gz": {Data: []byte("gz")},
+	}
+	srv := NewServer(
+		WithStatic("/admin", fsys, WithStaticMaxAge(time.Hour)),
+		WithStatic("/", fsys, WithSPA()),
+	)
+	srv.GET("/api/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	get := func(target string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		return serveRequest(srv, req)
+	}
+
+	w := get("/admin/assets/app.css", nil)
+	if w.Code != http.StatusOK || w.Body.String() != "body{}" || w.Header().Get("Cache-Control") != "public, max-age=3600" || w.Header().Get("ETag") == "" {
+		t.Errorf("unexpected response %d %v %s", w.Code, w.Header(), w.Body.String())
+	}
+	if w = get("/admin/assets/app.css", map[string]string{"If-None-Match": w.Header().Get("ETag")}); w.Code != http.StatusNotModified {
+		t.Errorf("expect 304, got %d", w.Code)
+	}
+
+	tests := []struct {
+		name     string
+		accept   string
+		body     string
+		encoding string
+	}{
+		{name: "br", accept: "gzip, deflate, br", body: "br", encoding: "br"},
+		{name: "gzip", accept: "gzip, br;q=0", body: "gz", encoding: "gzip"},
+		{name: "identity", accept: "", body: "console.log('app')"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get("/admin/assets/app.js", map[string]string{"Accept-Encoding": tt.accept})
+			if w.Body.String() != tt.body || w.Header().Get("Content-Encoding") != tt.encoding || w.Header().Get("Content-Type") != "text/javascript; charset=utf-8" {
+				t.Errorf("unexpected response %v %s", w.Header(), w.Body.String())
+			}
+		})
+	}
+
+	if w = get("/admin/", nil); w.Body.String() != "<html>index</html>" || w.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("expect the index, got %v %s", w.Header(), w.Body.String())
+	}
+	if w = get("/admin/missing", map[string]string{"Accept": "text/html"}); w.Code != http.StatusNotFound {
+		t.Errorf("expect 404 without SPA, got %d", w.Code)
+	}
+	if w = get("/admin/../../etc/passwd", nil); w.Code != http.StatusNotFound {
+		t.Errorf("expect 404 for the path out of the fs, got %d", w.Code)
+	}
+
+	if w = get("/api/ping", nil); w.Body.String() != "pong" {
+		t.Errorf("expect the api routes served, got %s", w.Body.String())
+	}
+	if w = get("/users/1", map[string]string{"Accept": "text/html,application/xhtml+xml"}); w.Body.String() != "<html>index</html>" {
+		t.Errorf("expect the SPA fallback, got %d %s", w.Code, w.Body.String())
+	}
+	if w = get("/assets/missing.js", map[string]string{"Accept": "text/html"}); w.Code != http.StatusNotFound {
+		t.Errorf("expect 404 for the missing asset, got %d", w.Code)
+	}
+}
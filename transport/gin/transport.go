@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/gin-gonic/gin"
 	"github.com/go-kratos/kratos/v2/transport"
 )
 
@@ -65,6 +66,34 @@ func (tr *Transport) PathTemplate() string {
 	return tr.pathTemplate
 }
 
+// transport is the engine middleware putting the Transport of every request into the request context,
+// so kratos middlewares can read it by transport.FromServerContext.
+// The operation and path template are the full path of the route, the reply header is the response header.
+// The request context is canceled after the handler timeout.
+func (s *Server) transport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tr := &Transport{
+			operation:    c.FullPath(),
+			pathTemplate: c.FullPath(),
+			reqHeader:    headerCarrier(c.Request.Header),
+			replyHeader:  headerCarrier(c.Writer.Header()),
+			request:      c.Request,
+		}
+		if s.endpoint != nil {
+			tr.endpoint = s.endpoint.String()
+		}
+		ctx := c.Request.Context()
+		if s.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+		}
+		c.Request = c.Request.WithContext(transport.NewServerContext(ctx, tr))
+		tr.request = c.Request
+		c.Next()
+	}
+}
+
 // SetOperation sets the transport operation.
 func SetOperation(ctx context.Context, op string) {
 	if tr, ok := transport.FromServerContext(ctx); ok {
@@ -3,10 +3,13 @@ package gin
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"sort"
 	"testing"
 
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/transport"
 )
 
@@ -92,3 +95,43 @@ func TestSetOperation(t *testing.T) {
 		t.Errorf("expect %v, got %v", "kratos", tr.operation)
 	}
 }
+
+func TestServer_Transport(t *testing.T) {
+	srv := NewServer(WithAddress("127.0.0.1:0"))
+	endpoint, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop(context.Background())
+	replyHeader := func(h middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				tr.ReplyHeader().Set("X-Operation", tr.Operation())
+			}
+			return h(ctx, req)
+		}
+	}
+	srv.Use(srv.Middleware(replyHeader))
+	srv.GET("/hygrothermograph/:id", func(c *gin.Context) {
+		tr, ok := transport.FromServerContext(c)
+		if !ok {
+			t.Fatal("expect the transport in the context")
+		}
+		ht := tr.(Transporter)
+		if tr.Kind() != KindGin || tr.Endpoint() != endpoint.String() || ht.PathTemplate() != "/hygrothermograph/:id" {
+			t.Errorf("unexpected transport %+v", tr)
+		}
+		if tr.RequestHeader().Get("X-Uid") != "42" || ht.Request().URL.Path != "/hygrothermograph/1" {
+			t.Errorf("unexpected request %v", ht.Request())
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/hygrothermograph/1", nil)
+	req.Header.Set("X-Uid", "42")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("X-Operation") != "/hygrothermograph/:id" {
+		t.Errorf("expect the reply header flushed, got %d %v", w.Code, w.Header())
+	}
+}
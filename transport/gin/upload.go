@@ -0,0 +1,217 @@
+package gin
+
+import (
+	"bufio"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/google/uuid"
+	"github.com/jiushengTech/common/internal/datedir"
+)
+
+var (
+	// ErrFileTooLarge 上传的文件超过 WithUploadMaxSize 的限制
+	ErrFileTooLarge = errors.New(http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "file too large")
+	// ErrFileTypeNotAllowed 上传的文件类型不在 WithUploadAllowTypes 中
+	ErrFileTypeNotAllowed = errors.New(http.StatusUnsupportedMediaType, "FILE_TYPE_NOT_ALLOWED", "file type not allowed")
+	// ErrTooManyFiles 上传的文件数超过 WithUploadMaxFiles 的限制
+	ErrTooManyFiles = errors.BadRequest("TOO_MANY_FILES", "too many files")
+	// ErrNotMultipart 上传请求不是 multipart/form-data
+	ErrNotMultipart = errors.BadRequest("NOT_MULTIPART", "request is not multipart/form-data")
+)
+
+// UploadOption WithUpload 的配置
+type UploadOption func(*uploadOptions)
+
+type uploadOptions struct {
+	maxSize    int64
+	maxFiles   int
+	allowTypes []string
+}
+
+// WithUploadMaxSize 单个文件的最大字节数，默认 32MB
+func WithUploadMaxSize(size int64) UploadOption {
+	return func(o *uploadOptions) {
+		o.maxSize = size
+	}
+}
+
+// WithUploadMaxFiles 单次请求的最大文件数，默认不限制
+func WithUploadMaxFiles(n int) UploadOption {
+	return func(o *uploadOptions) {
+		o.maxFiles = n
+	}
+}
+
+// WithUploadAllowTypes 允许的文件类型，根据文件内容识别，如 image/png，以 / 结尾时匹配前缀，如 image/
+func WithUploadAllowTypes(types ...string) UploadOption {
+	return func(o *uploadOptions) {
+		o.allowTypes = types
+	}
+}
+
+// UploadedFile 上传后的文件，Path 为相对于上传目录的路径，如 2025-01-02/<uuid>.png
+type UploadedFile struct {
+	Field       string `json:"field"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+}
+
+// WithUpload 在 route 注册 POST 上传接口，见 Server.Upload
+func WithUpload(route, dir string, opts ...UploadOption) ServerOption {
+	return func(s *Server) {
+		s.Engine.POST(route, s.Upload(dir, opts...))
+	}
+}
+
+// Upload 返回上传处理函数，以流的方式将 multipart 请求中的文件写入 dir 下的日期目录（YYYY-MM-DD），
+// 文件名为 uuid 加原扩展名，以 WithResponseEncoder 返回 []UploadedFile。
+// 任一文件失败时删除本次请求已写入的文件
+func (s *Server) Upload(dir string, opts ...UploadOption) gin.HandlerFunc {
+	o := &uploadOptions{maxSize: 32 << 20}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(c *gin.Context) {
+		files, err := o.save(c.Request, dir)
+		if err != nil {
+			for _, f := range files {
+				_ = os.Remove(filepath.Join(dir, filepath.FromSlash(f.Path)))
+			}
+			s.abort(c, err)
+			return
+		}
+		if err = s.enc(c.Writer, c.Request, files); err != nil {
+			s.abort(c, err)
+		}
+	}
+}
+
+func (o *uploadOptions) save(r *http.Request, dir string) ([]*UploadedFile, error) {
+	// the multipart parser may replace the body errors with its own
+	body := &uploadReader{r: r.Body}
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{body, r.Body}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, ErrNotMultipart
+	}
+	dateDir := datedir.Join(dir)
+	files := make([]*UploadedFile, 0)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return files, readError(body, err)
+		}
+		if part.FileName() == "" {
+			_ = part.Close()
+			continue
+		}
+		if o.maxFiles > 0 && len(files) >= o.maxFiles {
+			_ = part.Close()
+			return files, ErrTooManyFiles
+		}
+		if err = os.MkdirAll(dateDir, 0755); err != nil {
+			_ = part.Close()
+			return files, errors.InternalServer("UPLOAD", err.Error())
+		}
+		f, err := o.saveFile(body, part, dateDir)
+		_ = part.Close()
+		if f != nil {
+			files = append(files, f)
+		}
+		if err != nil {
+			return files, err
+		}
+	}
+}
+
+// saveFile streams the part to dir, the file is returned to be removed even if it fails.
+func (o *uploadOptions) saveFile(body *uploadReader, part *multipart.Part, dir string) (*UploadedFile, error) {
+	src := &uploadReader{r: part}
+	br := bufio.NewReaderSize(src, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, readError(body, err)
+	}
+	contentType := http.DetectContentType(head)
+	if !o.allowType(contentType) {
+		return nil, ErrFileTypeNotAllowed
+	}
+
+	name := uuid.NewString() + strings.ToLower(filepath.Ext(part.FileName()))
+	dst, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, errors.InternalServer("UPLOAD", err.Error())
+	}
+	f := &UploadedFile{
+		Field:       part.FormName(),
+		Name:        filepath.Base(part.FileName()),
+		Path:        filepath.Base(dir) + "/" + name,
+		ContentType: contentType,
+	}
+	f.Size, err = io.Copy(dst, io.LimitReader(br, o.maxSize+1))
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if src.err != nil {
+		return f, readError(body, src.err)
+	}
+	if err != nil {
+		return f, errors.InternalServer("UPLOAD", err.Error())
+	}
+	if f.Size > o.maxSize {
+		return f, ErrFileTooLarge
+	}
+	return f, nil
+}
+
+// uploadReader records the error of reading the request, to tell it from the error of writing the file.
+type uploadReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// readError maps the error of reading the request body, the body exceeding WithBodyLimit to 413,
+// and the truncated or malformed multipart body to 400.
+func readError(body *uploadReader, err error) error {
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) || errors.As(body.err, &mbe) {
+		return ErrBodyTooLarge
+	}
+	return errors.BadRequest("BAD_MULTIPART", err.Error())
+}
+
+func (o *uploadOptions) allowType(contentType string) bool {
+	if len(o.allowTypes) == 0 {
+		return true
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	for _, t := range o.allowTypes {
+		if strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t) || mediaType == t {
+			return true
+		}
+	}
+	return false
+}
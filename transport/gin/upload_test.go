@@ -0,0 +1,110 @@
+package gin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jiushengTech/common/internal/datedir"
+)
+
+func multipartRequest(t *testing.T, target string, files map[string][]byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("desc", "ignored")
+	for name, content := range files {
+		fw, err := mw.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fw.Write(content)
+	}
+	_ = mw.Close()
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestServer_Upload(t *testing.T) {
+	dir := t.TempDir()
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+	srv := NewServer(WithUpload("/upload", dir,
+		WithUploadMaxSize(64),
+		WithUploadMaxFiles(2),
+		WithUploadAllowTypes("image/", "text/plain"),
+	))
+
+	w := serveRequest(srv, multipartRequest(t, "/upload", map[string][]byte{"logo.PNG": png}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expect 200, got %d %s", w.Code, w.Body.String())
+	}
+	var files []*UploadedFile
+	if err := json.Unmarshal(w.Body.Bytes(), &files); err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name != "logo.PNG" || files[0].ContentType != "image/png" || files[0].Size != int64(len(png)) {
+		t.Fatalf("unexpected files %s", w.Body.String())
+	}
+	if !strings.HasSuffix(files[0].Path, ".png") || filepath.Dir(files[0].Path) != filepath.Base(datedir.Join(dir)) {
+		t.Errorf("unexpected path %s", files[0].Path)
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, files[0].Path)); err != nil || !bytes.Equal(b, png) {
+		t.Errorf("unexpected file content %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		files map[string][]byte
+		code  int
+	}{
+		{name: "too large", files: map[string][]byte{"a.txt": bytes.Repeat([]byte("a"), 65)}, code: http.StatusRequestEntityTooLarge},
+		{name: "type", files: map[string][]byte{"a.zip": []byte("PK\x03\x04")}, code: http.StatusUnsupportedMediaType},
+		{name: "too many", files: map[string][]byte{"a.txt": []byte("a"), "b.txt": []byte("b"), "c.txt": []byte("c")}, code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serveRequest(srv, multipartRequest(t, "/upload", tt.files)); w.Code != tt.code {
+				t.Errorf("expect %d, got %d %s", tt.code, w.Code, w.Body.String())
+			}
+			entries, _ := os.ReadDir(datedir.Join(dir))
+			if len(entries) != 1 {
+				t.Errorf("expect the files of the failed request removed, got %d files", len(entries))
+			}
+		})
+	}
+
+	if w = serve(srv, http.MethodPost, "/upload", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expect 400 for the non multipart request, got %d", w.Code)
+	}
+}
+
+func TestServer_UploadReadError(t *testing.T) {
+	dir := t.TempDir()
+	srv := NewServer(WithBodyLimit(1024), WithUpload("/upload", dir))
+
+	// the body limit is hit while streaming, the content length is unknown
+	req := multipartRequest(t, "/upload", map[string][]byte{"a.txt": bytes.Repeat([]byte("a"), 2048)})
+	req.ContentLength = -1
+	if w := serveRequest(srv, req); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expect 413, got %d %s", w.Code, w.Body.String())
+	}
+
+	req = multipartRequest(t, "/upload", map[string][]byte{"a.txt": []byte("abc")})
+	body, _ := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body[:len(body)-20]))
+	req.ContentLength = int64(len(body) - 20)
+	if w := serveRequest(srv, req); w.Code != http.StatusBadRequest {
+		t.Errorf("expect 400 for the truncated body, got %d %s", w.Code, w.Body.String())
+	}
+	if entries, _ := os.ReadDir(datedir.Join(dir)); len(entries) != 0 {
+		t.Errorf("expect the files of the failed requests removed, got %d files", len(entries))
+	}
+}
@@ -0,0 +1,81 @@
+// Package host extracts the address a server is reachable at, shared by the transports.
+package host
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// Port returns the real port of the listener.
+func Port(lis net.Listener) (int, bool) {
+	if addr, ok := lis.Addr().(*net.TCPAddr); ok {
+		return addr.Port, true
+	}
+	return 0, false
+}
+
+// Extract returns the address to register of a server listening on hostPort.
+// The port of lis is used if it's not nil, so ephemeral ports like :0 are reported,
+// and a private IP of the interfaces is used when the host is empty or unspecified.
+func Extract(hostPort string, lis net.Listener) (string, error) {
+	addr, port, err := net.SplitHostPort(hostPort)
+	if err != nil && lis == nil {
+		return "", err
+	}
+	if lis != nil {
+		p, ok := Port(lis)
+		if !ok {
+			return "", fmt.Errorf("failed to extract port: %v", lis.Addr())
+		}
+		port = strconv.Itoa(p)
+	}
+	if ip := net.ParseIP(addr); addr != "" && (ip == nil || !ip.IsUnspecified()) {
+		return net.JoinHostPort(addr, port), nil
+	}
+	ip, err := privateIP()
+	if err != nil {
+		return "", err
+	}
+	if ip == nil {
+		return net.JoinHostPort("127.0.0.1", port), nil
+	}
+	return net.JoinHostPort(ip.String(), port), nil
+}
+
+// privateIP returns the first global unicast IP of the up interface with the lowest index.
+func privateIP() (net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		res      net.IP
+		minIndex = int(^uint(0) >> 1)
+	)
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Index >= minIndex {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, rawAddr := range addrs {
+			var ip net.IP
+			switch addr := rawAddr.(type) {
+			case *net.IPAddr:
+				ip = addr.IP
+			case *net.IPNet:
+				ip = addr.IP
+			default:
+				continue
+			}
+			if ip.IsGlobalUnicast() && !ip.IsInterfaceLocalMulticast() {
+				res, minIndex = ip, iface.Index
+				break
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,24 @@
+// Package origin matches the request origins against the allow-lists, shared by the transports.
+package origin
+
+import "strings"
+
+// Match reports whether origin is allowed, and whether it's allowed by "*".
+// The allow-list supports "*" for all origins and "https://*.example.com" for the subdomains.
+func Match(allowOrigins []string, origin string) (allowed bool, wildcard bool) {
+	for _, o := range allowOrigins {
+		switch {
+		case o == "*":
+			return true, true
+		case strings.EqualFold(o, origin):
+			return true, false
+		case strings.Contains(o, "*."):
+			prefix, suffix, _ := strings.Cut(strings.ToLower(o), "*")
+			lower := strings.ToLower(origin)
+			if len(lower) > len(prefix)+len(suffix) && strings.HasPrefix(lower, prefix) && strings.HasSuffix(lower, suffix) {
+				return true, false
+			}
+		}
+	}
+	return false, false
+}
@@ -1,6 +1,10 @@
 package udp
 
-import "time"
+import (
+	"time"
+
+	"github.com/jiushengTech/common/log"
+)
 
 // Config UDP服务器配置
 type Config struct {
@@ -12,6 +16,7 @@ type Config struct {
 	BufferSize      int
 	MaxPacketSize   int
 	EnableBroadcast bool
+	Logger          log.Logger
 }
 
 // Option UDP配置选项
@@ -64,3 +69,10 @@ func WithBroadcast(enable bool) Option {
 		c.EnableBroadcast = enable
 	}
 }
+
+// WithLogger 设置日志记录器，默认使用 log.GetLogger()
+func WithLogger(l log.Logger) Option {
+	return func(c *Config) {
+		c.Logger = l
+	}
+}
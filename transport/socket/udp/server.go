@@ -8,8 +8,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jiushengTech/common/log"
 	"github.com/jiushengTech/common/transport/socket"
 
+	klog "github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/transport"
 )
 
@@ -21,6 +23,7 @@ type Server struct {
 	closedChan chan struct{}
 	udpConn    *net.UDPConn
 	isStarted  bool
+	log        *klog.Helper
 }
 
 var (
@@ -37,6 +40,7 @@ func NewServer(opts ...Option) *Server {
 		ReadTimeout:     30 * time.Second,
 		WriteTimeout:    10 * time.Second,
 		EnableBroadcast: false,
+		Logger:          log.GetLogger(),
 	}
 
 	for _, opt := range opts {
@@ -46,6 +50,7 @@ func NewServer(opts ...Option) *Server {
 	return &Server{
 		config:     config,
 		closedChan: make(chan struct{}),
+		log:        log.NewHelper(config.Logger),
 	}
 }
 
@@ -86,7 +91,7 @@ func (s *Server) Stop(ctx context.Context) error {
 	defer s.mu.Unlock()
 
 	if s.closed {
-		fmt.Println("UDP Server 已经关闭，无需重复 Stop")
+		s.log.Warn("UDP Server 已经关闭，无需重复 Stop")
 		return nil
 	}
 
@@ -0,0 +1,161 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// Backplane 在多个节点之间转发 Broadcast 和 BroadcastRoom 的消息，
+// 每个节点发布的消息都会被所有订阅的节点（包括自己）收到
+type Backplane interface {
+	// Publish 发布消息
+	Publish(ctx context.Context, data []byte) error
+	// Subscribe 订阅所有节点发布的消息，不阻塞，ctx 结束时取消订阅。
+	// handler 需按发布的顺序依次调用
+	Subscribe(ctx context.Context, handler func(data []byte)) error
+}
+
+// recvExpiry is how long the last sequence of a node and stream is kept after its last frame,
+// so the stopped nodes are forgotten.
+var recvExpiry = 10 * time.Minute
+
+// recvState is the last sequence received of a node and stream.
+type recvState struct {
+	seq  uint64
+	seen time.Time
+}
+
+// backplaneFrame is the message published to the backplane.
+// Seq increases per node across all rooms, so the publisher keeps no state per room,
+// the receivers drop the frames not newer than the last one of the node and room,
+// so the deliveries are deduplicated and ordered per room.
+type backplaneFrame struct {
+	Node string `json:"node"`
+	Seq  uint64 `json:"seq"`
+	All  bool   `json:"all,omitempty"`
+	Room string `json:"room,omitempty"`
+	Data []byte `json:"data"`
+}
+
+func (f *backplaneFrame) stream() string {
+	if f.All {
+		return "all"
+	}
+	return "room:" + f.Room
+}
+
+// publish sends the broadcast to the other nodes, the local sessions are sent by the caller.
+func (s *WebSocketServer) publish(all bool, room string, msg []byte) {
+	if s.backplane == nil {
+		return
+	}
+	f := &backplaneFrame{Node: s.node, All: all, Room: room, Data: msg}
+	// the lock keeps the order of publishing the same as the sequence
+	s.pubMu.Lock()
+	defer s.pubMu.Unlock()
+	s.pubSeq++
+	f.Seq = s.pubSeq
+	data, err := json.Marshal(f)
+	if err != nil {
+		log.Errorf("[WEBSOCKET] marshal backplane message failed: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.writeWait)
+	defer cancel()
+	if err = s.backplane.Publish(ctx, data); err != nil {
+		log.Errorf("[WEBSOCKET] publish to backplane failed: %v", err)
+	}
+}
+
+// receive sends the broadcasts of the other nodes to the local sessions.
+func (s *WebSocketServer) receive(data []byte) {
+	f := new(backplaneFrame)
+	if err := json.Unmarshal(data, f); err != nil {
+		log.Errorf("[WEBSOCKET] unmarshal backplane message failed: %v", err)
+		return
+	}
+	if f.Node == s.node {
+		return
+	}
+	key := f.Node + "/" + f.stream()
+	now := time.Now()
+	s.recvMu.Lock()
+	s.pruneRecv(now)
+	st, ok := s.recvSeq[key]
+	if ok && f.Seq <= st.seq {
+		// duplicated, or arrived after a newer one
+		s.recvMu.Unlock()
+		return
+	}
+	s.recvSeq[key] = &recvState{seq: f.Seq, seen: now}
+	s.recvMu.Unlock()
+
+	if f.All {
+		s.send(s.hub.all(), f.Data)
+	} else {
+		s.send(s.hub.room(f.Room), f.Data)
+	}
+}
+
+// pruneRecv drops the sequences not received within recvExpiry, at most once per recvExpiry.
+// The caller must hold recvMu.
+func (s *WebSocketServer) pruneRecv(now time.Time) {
+	if now.Sub(s.recvPrune) < recvExpiry {
+		return
+	}
+	s.recvPrune = now
+	for key, st := range s.recvSeq {
+		if now.Sub(st.seen) >= recvExpiry {
+			delete(s.recvSeq, key)
+		}
+	}
+}
+
+// MemoryBackplane 是进程内的 Backplane，用于单机多个服务器实例和测试
+type MemoryBackplane struct {
+	// pubMu keeps the handlers called in the order of publishing
+	pubMu    sync.Mutex
+	mu       sync.Mutex
+	next     int
+	handlers map[int]func([]byte)
+}
+
+// NewMemoryBackplane 创建进程内的 Backplane
+func NewMemoryBackplane() *MemoryBackplane {
+	return &MemoryBackplane{handlers: make(map[int]func([]byte))}
+}
+
+// Publish 依次调用所有订阅者，订阅者可在其中取消订阅
+func (b *MemoryBackplane) Publish(_ context.Context, data []byte) error {
+	b.pubMu.Lock()
+	defer b.pubMu.Unlock()
+	b.mu.Lock()
+	handlers := make([]func([]byte), 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.Unlock()
+	for _, h := range handlers {
+		h(data)
+	}
+	return nil
+}
+
+// Subscribe 订阅消息，ctx 结束时取消订阅
+func (b *MemoryBackplane) Subscribe(ctx context.Context, handler func([]byte)) error {
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+	context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	})
+	return nil
+}
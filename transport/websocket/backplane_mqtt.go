@@ -0,0 +1,44 @@
+package websocket
+
+import (
+	"context"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/go-kratos/kratos/v2/log"
+
+	"github.com/jiushengTech/common/client/mqtt"
+)
+
+// MQTTBackplane 通过 MQTT 主题转发消息，所有节点需使用同一个主题，
+// 客户端需已连接，并保持默认的按顺序分发消息
+type MQTTBackplane struct {
+	client *mqtt.Client
+	topic  string
+	qos    byte
+}
+
+// NewMQTTBackplane 创建使用 MQTT 主题的 Backplane，qos 至少为 1 时断线重连期间的消息不会丢失，重复的消息会被去重
+func NewMQTTBackplane(client *mqtt.Client, topic string, qos byte) *MQTTBackplane {
+	return &MQTTBackplane{client: client, topic: topic, qos: qos}
+}
+
+// Publish 发布消息
+func (b *MQTTBackplane) Publish(_ context.Context, data []byte) error {
+	return b.client.Publish(b.topic, b.qos, false, data)
+}
+
+// Subscribe 订阅主题，ctx 结束时取消订阅
+func (b *MQTTBackplane) Subscribe(ctx context.Context, handler func([]byte)) error {
+	err := b.client.Subscribe(b.topic, b.qos, func(_ paho.Client, m paho.Message) {
+		handler(m.Payload())
+	})
+	if err != nil {
+		return err
+	}
+	context.AfterFunc(ctx, func() {
+		if err := b.client.Unsubscribe(b.topic); err != nil {
+			log.Errorf("[WEBSOCKET] unsubscribe %s failed: %v", b.topic, err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,95 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/gomodule/redigo/redis"
+)
+
+// RedisBackplane 通过 Redis 发布订阅转发消息，所有节点需使用同一个频道。
+// Redis 发布订阅不保证送达，断线重连期间的消息会丢失
+type RedisBackplane struct {
+	pool    *redis.Pool
+	channel string
+}
+
+// NewRedisBackplane 创建使用 Redis 频道的 Backplane
+func NewRedisBackplane(pool *redis.Pool, channel string) *RedisBackplane {
+	return &RedisBackplane{pool: pool, channel: channel}
+}
+
+// Publish 发布消息
+func (b *RedisBackplane) Publish(ctx context.Context, data []byte) error {
+	conn, err := b.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = redis.DoContext(conn, ctx, "PUBLISH", b.channel, data)
+	return err
+}
+
+// Subscribe 订阅频道，连接断开时每秒重试，ctx 结束时取消订阅
+func (b *RedisBackplane) Subscribe(ctx context.Context, handler func([]byte)) error {
+	psc, err := b.subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			b.receive(ctx, psc, handler)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				if psc, err = b.subscribe(ctx); err == nil {
+					break
+				}
+				log.Errorf("[WEBSOCKET] subscribe %s failed: %v", b.channel, err)
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *RedisBackplane) subscribe(ctx context.Context) (*redis.PubSubConn, error) {
+	conn, err := b.pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	psc := &redis.PubSubConn{Conn: conn}
+	if err = psc.Subscribe(b.channel); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return psc, nil
+}
+
+// receive calls handler until the connection fails or ctx is done.
+func (b *RedisBackplane) receive(ctx context.Context, psc *redis.PubSubConn, handler func([]byte)) {
+	// closing the connection unblocks Receive
+	stop := context.AfterFunc(ctx, func() {
+		_ = psc.Unsubscribe()
+		_ = psc.Close()
+	})
+	defer func() {
+		if stop() {
+			_ = psc.Close()
+		}
+	}()
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			handler(v.Data)
+		case error:
+			if ctx.Err() == nil {
+				log.Errorf("[WEBSOCKET] receive %s failed: %v", b.channel, v)
+			}
+			return
+		}
+	}
+}
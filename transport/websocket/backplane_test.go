@@ -0,0 +1,169 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestBackplane_Memory(t *testing.T) {
+	b := NewMemoryBackplane()
+	join := WithOnConnect(func(sess *Session) { sess.Join("news") })
+	s1 := NewWebSocketServer(WithBackplane(b), join)
+	s2 := NewWebSocketServer(WithBackplane(b), join)
+	for _, s := range []*WebSocketServer{s1, s2, s2} {
+		if err := s.Subscribe(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv1, srv2 := httptest.NewServer(s1.Handler()), httptest.NewServer(s2.Handler())
+	defer srv1.Close()
+	defer srv2.Close()
+	c1, c2 := dial(t, srv1), dial(t, srv2)
+	defer c1.Close()
+	defer c2.Close()
+	waitFor(t, func() bool { return s1.Count() == 1 && s2.Count() == 1 })
+
+	s1.BroadcastRoom("news", []byte("1"))
+	s1.Broadcast([]byte("2"))
+	s2.BroadcastRoom("news", []byte("3"))
+	for _, c := range []*websocket.Conn{c1, c2} {
+		if msgs := read(t, c) + read(t, c) + read(t, c); msgs != "123" {
+			t.Errorf("expect each message once and in order, got %q", msgs)
+		}
+	}
+
+	// the subscription ends after stop
+	if err := s2.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return len(b.handlers) == 1
+	})
+	if err := s1.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackplane_DedupAndOrder(t *testing.T) {
+	s := NewWebSocketServer(WithBackplane(NewMemoryBackplane()), WithOnConnect(func(sess *Session) { sess.Join("news") }))
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	conn := dial(t, srv)
+	defer conn.Close()
+	waitFor(t, func() bool { return s.Count() == 1 })
+
+	frame := func(node string, seq uint64, data string) []byte {
+		b, _ := json.Marshal(&backplaneFrame{Node: node, Seq: seq, Room: "news", Data: []byte(data)})
+		return b
+	}
+	s.receive(frame("a", 1, "a1"))
+	s.receive(frame("a", 1, "a1 again"))
+	s.receive(frame("a", 3, "a3"))
+	s.receive(frame("a", 2, "a2 late"))
+	s.receive(frame("b", 1, "b1"))
+	s.receive(frame(s.node, 10, "own"))
+	for _, want := range []string{"a1", "a3", "b1"} {
+		if msg := read(t, conn); msg != want {
+			t.Errorf("expect %q, got %q", want, msg)
+		}
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	if _, msg, err := conn.ReadMessage(); err == nil {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+type failedBackplane struct{ *MemoryBackplane }
+
+func (failedBackplane) Subscribe(context.Context, func([]byte)) error {
+	return errors.New("unavailable")
+}
+
+func TestBackplane_SubscribeFailed(t *testing.T) {
+	s := NewWebSocketServer(WithAddr("127.0.0.1:0"), WithBackplane(failedBackplane{NewMemoryBackplane()}))
+	if err := s.Start(context.Background()); err == nil || err.Error() != "unavailable" {
+		t.Errorf("expect the subscribe error returned by Start, got %v", err)
+	}
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackplane_Expiry(t *testing.T) {
+	defer func(d time.Duration) { recvExpiry = d }(recvExpiry)
+	recvExpiry = 50 * time.Millisecond
+	s := NewWebSocketServer(WithBackplane(NewMemoryBackplane()))
+	frame := func(node string, seq uint64) []byte {
+		b, _ := json.Marshal(&backplaneFrame{Node: node, Seq: seq, All: true})
+		return b
+	}
+	s.receive(frame("a", 1))
+	s.receive(frame("b", 1))
+	time.Sleep(60 * time.Millisecond)
+	s.receive(frame("b", 2))
+	s.recvMu.Lock()
+	defer s.recvMu.Unlock()
+	if _, ok := s.recvSeq["a/all"]; ok || len(s.recvSeq) != 1 || s.recvSeq["b/all"].seq != 2 {
+		t.Errorf("expect the silent node expired, got %v", s.recvSeq)
+	}
+}
+
+func TestMemoryBackplane_Unsubscribe(t *testing.T) {
+	b := NewMemoryBackplane()
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []string
+	// the handler cancels its own subscription while being called
+	_ = b.Subscribe(ctx, func(data []byte) {
+		got = append(got, string(data))
+		cancel()
+	})
+	_ = b.Publish(context.Background(), []byte("1"))
+	waitFor(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return len(b.handlers) == 0
+	})
+	_ = b.Publish(context.Background(), []byte("2"))
+	if len(got) != 1 || got[0] != "1" {
+		t.Errorf("expect only the message before unsubscribing, got %v", got)
+	}
+}
+
+func TestBackplane_ManyRooms(t *testing.T) {
+	b := NewMemoryBackplane()
+	s1 := NewWebSocketServer(WithBackplane(b))
+	s2 := NewWebSocketServer(WithBackplane(b), WithOnConnect(func(sess *Session) { sess.Join("news") }))
+	for _, s := range []*WebSocketServer{s1, s2} {
+		if err := s.Subscribe(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		defer s.Stop(context.Background())
+	}
+	srv := httptest.NewServer(s2.Handler())
+	defer srv.Close()
+	conn := dial(t, srv)
+	defer conn.Close()
+	waitFor(t, func() bool { return s2.Count() == 1 })
+
+	// the sequence is shared by the rooms, the frames of a room are not contiguous
+	s1.BroadcastRoom("news", []byte("1"))
+	for i := 0; i < 100; i++ {
+		s1.BroadcastRoom(fmt.Sprintf("user:%d", i), []byte("private"))
+	}
+	s1.BroadcastRoom("news", []byte("2"))
+	if msgs := read(t, conn) + read(t, conn); msgs != "12" {
+		t.Errorf("expect the room messages in order, got %q", msgs)
+	}
+	if s1.pubSeq != 102 {
+		t.Errorf("expect 102 published, got %d", s1.pubSeq)
+	}
+}
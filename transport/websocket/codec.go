@@ -0,0 +1,176 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// JSONCodec 使用 JSON 文本帧：{"type":"chat","id":"1","payload":{...},"error":{"code":400,...}}，
+	// payload 为 proto.Message 时使用 protojson
+	JSONCodec Codec = jsonCodec{}
+	// ProtoCodec 使用 protobuf 二进制帧，payload 必须是 proto.Message，信封的结构为
+	//
+	//	message Message {
+	//	  string type = 1;
+	//	  string id = 2;
+	//	  bytes payload = 3;
+	//	  kratos.errors.Status error = 4;
+	//	}
+	ProtoCodec Codec = protoCodec{}
+)
+
+// Message 是消息协议的信封，Type 用于路由，ID 用于关联请求和响应，Error 不为空时是错误帧
+type Message struct {
+	Type    string
+	ID      string
+	Payload []byte
+	Error   *errors.Error
+}
+
+// Codec 编解码消息信封和其中的 payload
+type Codec interface {
+	Name() string
+	Marshal(msg *Message) ([]byte, error)
+	Unmarshal(data []byte, msg *Message) error
+	MarshalPayload(v any) ([]byte, error)
+	UnmarshalPayload(data []byte, v any) error
+}
+
+type jsonMessage struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Error   *errors.Status  `json:"error,omitempty"`
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(msg *Message) ([]byte, error) {
+	m := jsonMessage{Type: msg.Type, ID: msg.ID, Payload: msg.Payload}
+	if msg.Error != nil {
+		m.Error = &msg.Error.Status
+	}
+	return json.Marshal(&m)
+}
+
+func (jsonCodec) Unmarshal(data []byte, msg *Message) error {
+	var m jsonMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*msg = Message{Type: m.Type, ID: m.ID, Payload: m.Payload, Error: fromStatus(m.Error)}
+	return nil
+}
+
+func (jsonCodec) MarshalPayload(v any) ([]byte, error) {
+	return encoding.GetCodec("json").Marshal(v)
+}
+
+func (jsonCodec) UnmarshalPayload(data []byte, v any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return encoding.GetCodec("json").Unmarshal(data, v)
+}
+
+type protoCodec struct{}
+
+func (protoCodec) Name() string {
+	return "proto"
+}
+
+func (protoCodec) Marshal(msg *Message) ([]byte, error) {
+	var b []byte
+	if msg.Type != "" {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, msg.Type)
+	}
+	if msg.ID != "" {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, msg.ID)
+	}
+	if len(msg.Payload) > 0 {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, msg.Payload)
+	}
+	if msg.Error != nil {
+		st, err := proto.Marshal(&msg.Error.Status)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendBytes(b, st)
+	}
+	return b, nil
+}
+
+func (protoCodec) Unmarshal(data []byte, msg *Message) error {
+	*msg = Message{}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if num < 1 || num > 4 || typ != protowire.BytesType {
+			// unknown fields are skipped
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		switch num {
+		case 1:
+			msg.Type = string(v)
+		case 2:
+			msg.ID = string(v)
+		case 3:
+			msg.Payload = v
+		case 4:
+			st := new(errors.Status)
+			if err := proto.Unmarshal(v, st); err != nil {
+				return err
+			}
+			msg.Error = fromStatus(st)
+		}
+	}
+	return nil
+}
+
+func (protoCodec) MarshalPayload(v any) ([]byte, error) {
+	if _, ok := v.(proto.Message); !ok {
+		return nil, fmt.Errorf("websocket: payload %T is not a proto.Message", v)
+	}
+	return encoding.GetCodec("proto").Marshal(v)
+}
+
+func (protoCodec) UnmarshalPayload(data []byte, v any) error {
+	if _, ok := v.(proto.Message); !ok {
+		return fmt.Errorf("websocket: payload %T is not a proto.Message", v)
+	}
+	return encoding.GetCodec("proto").Unmarshal(data, v)
+}
+
+func fromStatus(st *errors.Status) *errors.Error {
+	if st == nil {
+		return nil
+	}
+	return errors.New(int(st.Code), st.Reason, st.Message).WithMetadata(st.Metadata)
+}
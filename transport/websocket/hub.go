@@ -0,0 +1,99 @@
+package websocket
+
+import "sync"
+
+// hub 管理所有连接和房间
+type hub struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+	rooms    map[string]map[string]*Session
+	// joined 记录每个连接加入的房间，关闭时从房间中移除
+	joined map[string]map[string]struct{}
+}
+
+func newHub() *hub {
+	return &hub{
+		sessions: make(map[string]*Session),
+		rooms:    make(map[string]map[string]*Session),
+		joined:   make(map[string]map[string]struct{}),
+	}
+}
+
+func (h *hub) register(s *Session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessions[s.id] = s
+}
+
+func (h *hub) unregister(s *Session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sessions, s.id)
+	for room := range h.joined[s.id] {
+		h.removeFromRoom(s.id, room)
+	}
+	delete(h.joined, s.id)
+}
+
+func (h *hub) get(id string) (*Session, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	s, ok := h.sessions[id]
+	return s, ok
+}
+
+func (h *hub) count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.sessions)
+}
+
+func (h *hub) all() []*Session {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	sessions := make([]*Session, 0, len(h.sessions))
+	for _, s := range h.sessions {
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+func (h *hub) join(s *Session, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.sessions[s.id]; !ok {
+		return
+	}
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[string]*Session)
+	}
+	h.rooms[room][s.id] = s
+	if h.joined[s.id] == nil {
+		h.joined[s.id] = make(map[string]struct{})
+	}
+	h.joined[s.id][room] = struct{}{}
+}
+
+func (h *hub) leave(s *Session, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeFromRoom(s.id, room)
+	delete(h.joined[s.id], room)
+}
+
+func (h *hub) removeFromRoom(id, room string) {
+	delete(h.rooms[room], id)
+	if len(h.rooms[room]) == 0 {
+		delete(h.rooms, room)
+	}
+}
+
+func (h *hub) room(room string) []*Session {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	sessions := make([]*Session, 0, len(h.rooms[room]))
+	for _, s := range h.rooms[room] {
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
@@ -1,6 +1,12 @@
 package websocket
 
-import "github.com/gorilla/websocket"
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
 
 // Option 是 WebSocket 客户端选项类型
 type Option func(o *WebSocketServer)
@@ -12,6 +18,65 @@ func WithAddr(addr string) Option {
 	}
 }
 
+// WithNetwork 设置监听的网络类型，默认 tcp
+func WithNetwork(network string) Option {
+	return func(s *WebSocketServer) {
+		s.network = network
+	}
+}
+
+// WithListener 使用已有的监听器
+func WithListener(lis net.Listener) Option {
+	return func(s *WebSocketServer) {
+		s.lis = lis
+	}
+}
+
+// WithTLSConfig 设置 TLS 配置，启用 wss
+func WithTLSConfig(c *tls.Config) Option {
+	return func(s *WebSocketServer) {
+		s.tlsConf = c
+	}
+}
+
+// WithTLSCertFile 设置 TLS 证书和私钥文件，启用 wss
+func WithTLSCertFile(certFile, keyFile string) Option {
+	return func(s *WebSocketServer) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
+// WithAllowOrigins 设置允许连接的 Origin 白名单，支持 "*" 和 "https://*.example.com" 形式的通配，
+// 未设置时只允许同源连接，没有 Origin 头的非浏览器客户端总是允许
+func WithAllowOrigins(origins ...string) Option {
+	return func(s *WebSocketServer) {
+		s.allowOrigins = origins
+	}
+}
+
+// WithAuth 设置升级连接前的认证函数，认证失败时返回 401
+func WithAuth(fn AuthFunc) Option {
+	return func(s *WebSocketServer) {
+		s.auth = fn
+	}
+}
+
+// WithTokenQuery 设置携带 token 的查询参数名，默认 token，请求头 Authorization: Bearer 优先
+func WithTokenQuery(name string) Option {
+	return func(s *WebSocketServer) {
+		s.tokenQuery = name
+	}
+}
+
+// WithBackplane 设置多节点间转发广播的 Backplane，Broadcast 和 BroadcastRoom 会发送到所有节点，
+// 同一节点发往同一房间的消息在其它节点去重并保持顺序。Start 时订阅，仅通过 Handler 挂载时需调用 Subscribe
+func WithBackplane(b Backplane) Option {
+	return func(s *WebSocketServer) {
+		s.backplane = b
+	}
+}
+
 // WithURL 设置 WebSocket 连接的 url
 func WithURL(u string) Option {
 	return func(c *WebSocketServer) {
@@ -25,3 +90,78 @@ func WithUpgrader(upgrader *websocket.Upgrader) Option {
 		c.upgrader = upgrader
 	}
 }
+
+// WithMessageType 设置发送消息的类型，websocket.TextMessage（默认）或 websocket.BinaryMessage
+func WithMessageType(messageType int) Option {
+	return func(s *WebSocketServer) {
+		s.messageType = messageType
+	}
+}
+
+// WithSendBuffer 设置每个连接的发送缓冲区大小（消息数），默认 256
+func WithSendBuffer(size int) Option {
+	return func(s *WebSocketServer) {
+		s.sendBuffer = size
+	}
+}
+
+// WithMaxMessageSize 设置接收消息的最大字节数，默认 64KB
+func WithMaxMessageSize(size int64) Option {
+	return func(s *WebSocketServer) {
+		s.maxMessageSize = size
+	}
+}
+
+// WithWriteWait 设置写消息的超时，默认 10s
+func WithWriteWait(d time.Duration) Option {
+	return func(s *WebSocketServer) {
+		s.writeWait = d
+	}
+}
+
+// WithPongWait 设置等待 pong 的超时，超时未收到消息的连接会被关闭，默认 60s
+func WithPongWait(d time.Duration) Option {
+	return func(s *WebSocketServer) {
+		s.pongWait = d
+	}
+}
+
+// WithPingPeriod 设置发送 ping 的间隔，须小于 pongWait，默认为 pongWait 的 9/10
+func WithPingPeriod(d time.Duration) Option {
+	return func(s *WebSocketServer) {
+		s.pingPeriod = d
+	}
+}
+
+// WithOnConnect 设置连接建立后的回调
+func WithOnConnect(fn func(*Session)) Option {
+	return func(s *WebSocketServer) {
+		s.onConnect = fn
+	}
+}
+
+// WithOnMessage 设置收到消息的回调，在连接的读协程中执行
+func WithOnMessage(fn func(*Session, []byte)) Option {
+	return func(s *WebSocketServer) {
+		s.onMessage = fn
+	}
+}
+
+// WithOnClose 设置连接关闭后的回调，err 为异常关闭的原因，正常关闭时为 nil
+func WithOnClose(fn func(*Session, error)) Option {
+	return func(s *WebSocketServer) {
+		s.onClose = fn
+	}
+}
+
+// WithRouter 使用消息路由处理连接收到的消息，会替换 WithOnMessage 设置的回调，
+// 使用 ProtoCodec 时发送二进制帧
+func WithRouter(r *Router) Option {
+	return func(s *WebSocketServer) {
+		r.server = s
+		s.onMessage = r.dispatch
+		if r.codec == ProtoCodec {
+			s.messageType = websocket.BinaryMessage
+		}
+	}
+}
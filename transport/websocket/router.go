@@ -0,0 +1,286 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/google/uuid"
+)
+
+// HandlerFunc 处理一种类型的消息，返回值作为响应的 payload
+type HandlerFunc func(ctx context.Context, sess *Session, msg *Message) (any, error)
+
+// RouterOption 是消息路由选项类型
+type RouterOption func(*Router)
+
+// WithCodec 设置消息的编解码，默认 JSONCodec
+func WithCodec(c Codec) RouterOption {
+	return func(r *Router) {
+		r.codec = c
+	}
+}
+
+// WithTimeout 设置处理消息和等待客户端响应的超时时间，默认 10 秒
+func WithTimeout(d time.Duration) RouterOption {
+	return func(r *Router) {
+		r.timeout = d
+	}
+}
+
+// WithMiddleware 设置所有消息处理函数的 kratos 中间件，
+// 中间件可通过 transport.FromServerContext 获取 Transport，Operation 为消息类型
+func WithMiddleware(ms ...middleware.Middleware) RouterOption {
+	return func(r *Router) {
+		r.ms = ms
+	}
+}
+
+type route struct {
+	decode  func(c Codec, msg *Message) (any, error)
+	handler middleware.Handler
+}
+
+type pending struct {
+	sess  *Session
+	reply chan *Message
+}
+
+// Router 在 WebSocket 帧之上实现 {type, id, payload} 的消息协议：
+// 按 type 路由到处理函数，带 id 的消息会收到同 type、同 id 的响应，处理失败时响应错误帧。
+// 通过 WithRouter 挂载到 WebSocketServer 后使用，处理函数在连接的读协程中依次执行，
+// 在其中向同一连接调用 Request 需另起协程，否则会等到超时
+type Router struct {
+	server  *WebSocketServer
+	codec   Codec
+	timeout time.Duration
+	ms      []middleware.Middleware
+
+	mu      sync.RWMutex
+	routes  map[string]*route
+	pending map[string]*pending
+}
+
+// NewRouter 创建消息路由
+func NewRouter(opts ...RouterOption) *Router {
+	r := &Router{
+		codec:   JSONCodec,
+		timeout: 10 * time.Second,
+		routes:  make(map[string]*route),
+		pending: make(map[string]*pending),
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// Handle 注册消息类型的处理函数，中间件收到的请求为 *Message
+func (r *Router) Handle(typ string, h HandlerFunc, ms ...middleware.Middleware) {
+	r.handle(typ, func(_ Codec, msg *Message) (any, error) {
+		return msg, nil
+	}, func(ctx context.Context, req any) (any, error) {
+		return h(ctx, sessionFromContext(ctx), req.(*Message))
+	}, ms)
+}
+
+// Handle 注册消息类型的处理函数，payload 解码为 *T 后经过中间件传给 fn，
+// 使用 ProtoCodec 时 *T 必须是 proto.Message
+func Handle[T any](r *Router, typ string, fn func(ctx context.Context, sess *Session, req *T) (any, error), ms ...middleware.Middleware) {
+	r.handle(typ, func(c Codec, msg *Message) (any, error) {
+		req := new(T)
+		if err := c.UnmarshalPayload(msg.Payload, req); err != nil {
+			return nil, err
+		}
+		return req, nil
+	}, func(ctx context.Context, req any) (any, error) {
+		return fn(ctx, sessionFromContext(ctx), req.(*T))
+	}, ms)
+}
+
+func (r *Router) handle(typ string, decode func(Codec, *Message) (any, error), h middleware.Handler, ms []middleware.Middleware) {
+	chain := make([]middleware.Middleware, 0, len(r.ms)+len(ms))
+	chain = append(append(chain, r.ms...), ms...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[typ] = &route{decode: decode, handler: middleware.Chain(chain...)(h)}
+}
+
+// Push 向指定连接推送消息
+func (r *Router) Push(sessionID, typ string, payload any) error {
+	data, err := r.marshal(&Message{Type: typ}, payload)
+	if err != nil {
+		return err
+	}
+	return r.server.Send(sessionID, data)
+}
+
+// PushRoom 向房间内的所有连接推送消息
+func (r *Router) PushRoom(room, typ string, payload any) error {
+	data, err := r.marshal(&Message{Type: typ}, payload)
+	if err != nil {
+		return err
+	}
+	r.server.BroadcastRoom(room, data)
+	return nil
+}
+
+// PushAll 向所有连接推送消息
+func (r *Router) PushAll(typ string, payload any) error {
+	data, err := r.marshal(&Message{Type: typ}, payload)
+	if err != nil {
+		return err
+	}
+	r.server.Broadcast(data)
+	return nil
+}
+
+// Request 向指定连接发送请求，等待客户端回复同 id 的响应并解码到 reply，reply 为 nil 时忽略响应的 payload。
+// ctx 没有截止时间时使用路由的超时时间，客户端回复错误帧时返回对应的 *errors.Error
+func (r *Router) Request(ctx context.Context, sessionID, typ string, req, reply any) error {
+	sess, ok := r.server.Session(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if _, ok := ctx.Deadline(); !ok && r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+	id := uuid.NewString()
+	data, err := r.marshal(&Message{Type: typ, ID: id}, req)
+	if err != nil {
+		return err
+	}
+	p := &pending{sess: sess, reply: make(chan *Message, 1)}
+	r.mu.Lock()
+	r.pending[id] = p
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, id)
+		r.mu.Unlock()
+	}()
+
+	if err = sess.Send(data); err != nil {
+		return err
+	}
+	select {
+	case msg := <-p.reply:
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if reply == nil {
+			return nil
+		}
+		return r.codec.UnmarshalPayload(msg.Payload, reply)
+	case <-sess.done:
+		return ErrSessionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dispatch is the OnMessage of the server, it resolves the responses of Request and routes the others.
+func (r *Router) dispatch(sess *Session, data []byte) {
+	msg := new(Message)
+	if err := r.codec.Unmarshal(data, msg); err != nil {
+		r.reply(sess, &Message{}, nil, errors.BadRequest("CODEC", err.Error()))
+		return
+	}
+	if msg.ID != "" && r.resolve(sess, msg) {
+		return
+	}
+	r.mu.RLock()
+	rt, ok := r.routes[msg.Type]
+	r.mu.RUnlock()
+	if !ok {
+		r.reply(sess, msg, nil, errors.NotFound("MESSAGE_NOT_FOUND", fmt.Sprintf("message type %q not found", msg.Type)))
+		return
+	}
+	req, err := rt.decode(r.codec, msg)
+	if err != nil {
+		r.reply(sess, msg, nil, errors.BadRequest("CODEC", err.Error()))
+		return
+	}
+
+	tr := &Transport{
+		operation:   msg.Type,
+		reqHeader:   headerCarrier(sess.Request().Header),
+		replyHeader: headerCarrier(http.Header{}),
+		session:     sess,
+	}
+	if r.server.endpoint != nil {
+		tr.endpoint = r.server.endpoint.String()
+	}
+	ctx := transport.NewServerContext(context.Background(), tr)
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+	resp, err := rt.handler(ctx, req)
+	if err != nil || msg.ID != "" {
+		// messages without id are notifications, only errors are replied
+		r.reply(sess, msg, resp, err)
+	}
+}
+
+// resolve passes the response to the waiting Request, and reports whether msg is a response.
+func (r *Router) resolve(sess *Session, msg *Message) bool {
+	r.mu.RLock()
+	p, ok := r.pending[msg.ID]
+	r.mu.RUnlock()
+	if !ok || p.sess != sess {
+		return false
+	}
+	select {
+	case p.reply <- msg:
+	default:
+		// duplicate response
+	}
+	return true
+}
+
+func (r *Router) reply(sess *Session, req *Message, payload any, err error) {
+	msg := &Message{Type: req.Type, ID: req.ID}
+	if err != nil {
+		msg.Error = errors.FromError(err)
+		payload = nil
+	}
+	data, err := r.marshal(msg, payload)
+	if err != nil {
+		log.Errorf("[WEBSOCKET] marshal reply of %s failed: %v", req.Type, err)
+		data, err = r.marshal(&Message{Type: req.Type, ID: req.ID, Error: errors.InternalServer("CODEC", err.Error())}, nil)
+		if err != nil {
+			return
+		}
+	}
+	_ = sess.Send(data)
+}
+
+func (r *Router) marshal(msg *Message, payload any) ([]byte, error) {
+	if payload != nil {
+		data, err := r.codec.MarshalPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		msg.Payload = data
+	}
+	return r.codec.Marshal(msg)
+}
+
+func sessionFromContext(ctx context.Context) *Session {
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		if tr, ok := tr.(*Transport); ok {
+			return tr.session
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type chatReq struct {
+	Text string `json:"text"`
+}
+
+func readMessage(t *testing.T, c Codec, conn *websocket.Conn) *Message {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := new(Message)
+	if err := c.Unmarshal(data, msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestRouter_JSON(t *testing.T) {
+	var operations []string
+	r := NewRouter(WithMiddleware(func(h middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, _ := transport.FromServerContext(ctx)
+			operations = append(operations, tr.Operation())
+			return h(ctx, req)
+		}
+	}))
+	Handle(r, "chat", func(ctx context.Context, sess *Session, req *chatReq) (any, error) {
+		if req.Text == "" {
+			return nil, errors.BadRequest("EMPTY_TEXT", "text is empty")
+		}
+		return &chatReq{Text: "echo " + req.Text}, nil
+	})
+	r.Handle("raw", func(ctx context.Context, sess *Session, msg *Message) (any, error) {
+		return map[string]int{"len": len(msg.Payload)}, nil
+	})
+	s := NewWebSocketServer(WithRouter(r))
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	conn := dial(t, srv)
+	defer conn.Close()
+
+	_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"chat","id":"1","payload":{"text":"hi"}}`))
+	msg := readMessage(t, JSONCodec, conn)
+	if msg.Type != "chat" || msg.ID != "1" || msg.Error != nil || string(msg.Payload) != `{"text":"echo hi"}` {
+		t.Errorf("unexpected response %+v", msg)
+	}
+
+	// notifications without id are not replied, errors are
+	_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"chat","payload":{"text":"hi"}}`))
+	_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"chat","id":"2","payload":{}}`))
+	msg = readMessage(t, JSONCodec, conn)
+	if msg.ID != "2" || msg.Error == nil || msg.Error.Code != 400 || msg.Error.Reason != "EMPTY_TEXT" {
+		t.Errorf("unexpected error frame %+v", msg)
+	}
+
+	_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"raw","id":"3","payload":[1,2]}`))
+	if msg = readMessage(t, JSONCodec, conn); string(msg.Payload) != `{"len":5}` {
+		t.Errorf("unexpected raw response %s", msg.Payload)
+	}
+
+	_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"missing","id":"4"}`))
+	if msg = readMessage(t, JSONCodec, conn); msg.Error == nil || msg.Error.Reason != "MESSAGE_NOT_FOUND" {
+		t.Errorf("unexpected not found frame %+v", msg)
+	}
+	_ = conn.WriteMessage(websocket.TextMessage, []byte(`not json`))
+	if msg = readMessage(t, JSONCodec, conn); msg.Error == nil || msg.Error.Reason != "CODEC" {
+		t.Errorf("unexpected codec error frame %+v", msg)
+	}
+
+	if len(operations) != 4 || operations[0] != "chat" || operations[3] != "raw" {
+		t.Errorf("unexpected middleware operations %v", operations)
+	}
+}
+
+func TestRouter_RequestAndPush(t *testing.T) {
+	r := NewRouter(WithTimeout(100 * time.Millisecond))
+	s := NewWebSocketServer(WithRouter(r))
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	conn := dial(t, srv)
+	defer conn.Close()
+	waitFor(t, func() bool { return s.Count() == 1 })
+	id := s.hub.all()[0].ID()
+
+	if err := r.Push(id, "notice", &chatReq{Text: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if msg := readMessage(t, JSONCodec, conn); msg.Type != "notice" || msg.ID != "" || string(msg.Payload) != `{"text":"hello"}` {
+		t.Errorf("unexpected push %+v", msg)
+	}
+
+	// the client answers the requests
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			msg := new(Message)
+			_ = JSONCodec.Unmarshal(data, msg)
+			var req chatReq
+			_ = json.Unmarshal(msg.Payload, &req)
+			switch req.Text {
+			case "ping":
+				msg.Payload = []byte(`{"text":"pong"}`)
+			case "fail":
+				msg.Payload, msg.Error = nil, errors.Forbidden("DENIED", "denied")
+			default:
+				continue
+			}
+			data, _ = JSONCodec.Marshal(msg)
+			_ = conn.WriteMessage(websocket.TextMessage, data)
+		}
+	}()
+
+	var reply chatReq
+	if err := r.Request(context.Background(), id, "ask", &chatReq{Text: "ping"}, &reply); err != nil || reply.Text != "pong" {
+		t.Errorf("unexpected reply %+v, %v", reply, err)
+	}
+	if err := r.Request(context.Background(), id, "ask", &chatReq{Text: "fail"}, nil); errors.Reason(err) != "DENIED" {
+		t.Errorf("expect the error frame returned, got %v", err)
+	}
+	if err := r.Request(context.Background(), id, "ask", &chatReq{Text: "silent"}, nil); err != context.DeadlineExceeded {
+		t.Errorf("expect timeout, got %v", err)
+	}
+	if err := r.Request(context.Background(), "missing", "ask", nil, nil); err != ErrSessionNotFound {
+		t.Errorf("expect ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestRouter_Proto(t *testing.T) {
+	r := NewRouter(WithCodec(ProtoCodec))
+	Handle(r, "upper", func(ctx context.Context, sess *Session, req *wrapperspb.StringValue) (any, error) {
+		return wrapperspb.String(req.GetValue() + "!"), nil
+	})
+	s := NewWebSocketServer(WithRouter(r))
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	conn := dial(t, srv)
+	defer conn.Close()
+
+	payload, _ := ProtoCodec.MarshalPayload(wrapperspb.String("hi"))
+	data, _ := ProtoCodec.Marshal(&Message{Type: "upper", ID: "1", Payload: payload})
+	_ = conn.WriteMessage(websocket.BinaryMessage, data)
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	mt, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := new(Message)
+	if err := ProtoCodec.Unmarshal(data, msg); err != nil {
+		t.Fatal(err)
+	}
+	var resp wrapperspb.StringValue
+	if err := ProtoCodec.UnmarshalPayload(msg.Payload, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if mt != websocket.BinaryMessage || msg.ID != "1" || resp.GetValue() != "hi!" {
+		t.Errorf("unexpected response %d %+v %q", mt, msg, resp.GetValue())
+	}
+
+	data, _ = ProtoCodec.Marshal(&Message{Type: "upper", ID: "2", Error: errors.NotFound("A", "b").WithMetadata(map[string]string{"k": "v"})})
+	if err := ProtoCodec.Unmarshal(data, msg); err != nil || msg.Error.Code != 404 || msg.Error.Metadata["k"] != "v" {
+		t.Errorf("unexpected error round trip %+v, %v", msg, err)
+	}
+	if _, err := ProtoCodec.MarshalPayload(&chatReq{}); err == nil {
+		t.Error("expect an error for a non proto payload")
+	}
+}
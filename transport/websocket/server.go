@@ -2,70 +2,334 @@ package websocket
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+
+	"github.com/jiushengTech/common/transport/internal/host"
+	"github.com/jiushengTech/common/transport/internal/origin"
 )
 
+// AuthFunc 在升级连接前认证，token 取自 Authorization: Bearer <token> 请求头或 token 查询参数，
+// 返回的信息（如用户 ID、claims）可通过 Session.Auth 获取，返回错误时拒绝连接
+type AuthFunc func(r *http.Request, token string) (any, error)
+
 var (
 	_ transport.Server     = (*WebSocketServer)(nil)
 	_ transport.Endpointer = (*WebSocketServer)(nil)
 )
 
-// WebSocketServer 是 WebSocket 服务器结构体
+// WebSocketServer 是 WebSocket 服务器结构体，管理多个连接。
+// 可以独立监听，也可以通过 Handler 挂载到 gin 等 HTTP 服务器上
 type WebSocketServer struct {
+	server   *http.Server
+	network  string
 	addr     string
-	conn     *websocket.Conn
+	lis      net.Listener
+	endpoint *url.URL
+	tlsConf  *tls.Config
+	certFile string
+	keyFile  string
 	url      string
 	upgrader *websocket.Upgrader
+
+	allowOrigins []string
+	auth         AuthFunc
+	tokenQuery   string
+
+	hub      *hub
+	sessions sync.WaitGroup
+	stopping atomic.Bool
+
+	backplane Backplane
+	node      string
+	subMu     sync.Mutex
+	cancel    context.CancelFunc
+	pubMu     sync.Mutex
+	pubSeq    uint64
+	recvMu    sync.Mutex
+	recvSeq   map[string]*recvState
+	recvPrune time.Time
+
+	messageType    int
+	sendBuffer     int
+	maxMessageSize int64
+	writeWait      time.Duration
+	pongWait       time.Duration
+	pingPeriod     time.Duration
+
+	onConnect func(*Session)
+	onMessage func(*Session, []byte)
+	onClose   func(*Session, error)
 }
 
 // NewWebSocketServer 创建新的 WebSocket 服务器
 func NewWebSocketServer(options ...Option) *WebSocketServer {
 	server := &WebSocketServer{
-		addr: "0.0.0.0",
+		network: "tcp",
+		addr:    ":0",
+		url:     "/",
 		upgrader: &websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
-			CheckOrigin: func(r *http.Request) bool {
-				return true
-			},
 		},
+		tokenQuery:     "token",
+		hub:            newHub(),
+		node:           uuid.NewString(),
+		recvSeq:        make(map[string]*recvState),
+		messageType:    websocket.TextMessage,
+		sendBuffer:     256,
+		maxMessageSize: 64 << 10,
+		writeWait:      10 * time.Second,
+		pongWait:       60 * time.Second,
 	}
 	// 应用选项
 	for _, option := range options {
 		option(server)
 	}
+	if server.pingPeriod <= 0 || server.pingPeriod >= server.pongWait {
+		server.pingPeriod = server.pongWait * 9 / 10
+	}
+	if len(server.allowOrigins) > 0 {
+		// 未设置白名单时使用 gorilla 默认的同源检查
+		server.upgrader.CheckOrigin = func(r *http.Request) bool {
+			o := r.Header.Get("Origin")
+			if o == "" {
+				return true
+			}
+			allowed, _ := origin.Match(server.allowOrigins, o)
+			return allowed
+		}
+	}
+	mux := http.NewServeMux()
+	mux.Handle(server.url, server.Handler())
+	server.server = &http.Server{Handler: mux, TLSConfig: server.tlsConf}
 	return server
 }
 
-// Stop 关闭 WebSocket 连接
+// Handler 返回处理 WebSocket 连接的 http.Handler，可挂载到其它服务器，
+// 如 gin 中的 srv.GET("/ws", gin.WrapH(ws.Handler()))。
+// 设置了 Backplane 时需调用 Subscribe 接收其它节点的广播，并在退出时调用 Stop 取消订阅
+func (s *WebSocketServer) Handler() http.Handler {
+	return http.HandlerFunc(s.wsHandler)
+}
+
+// Subscribe 订阅 Backplane，接收其它节点的广播，直到 Stop 被调用，未设置 Backplane 时不做任何事。
+// Start 会先调用 Subscribe，仅通过 Handler 挂载时需自行调用，重复调用不会重复订阅
+func (s *WebSocketServer) Subscribe(ctx context.Context) error {
+	if s.backplane == nil {
+		return nil
+	}
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	if s.cancel != nil {
+		return nil
+	}
+	// the subscription lives until Stop, not bound to the cancellation of ctx
+	sctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	if err := s.backplane.Subscribe(sctx, s.receive); err != nil {
+		cancel()
+		return err
+	}
+	s.cancel = cancel
+	return nil
+}
+
+// Stop 关闭监听，拒绝新的连接，向所有连接发送 1001 关闭帧后关闭，等待连接关闭直到 ctx 结束
 func (s *WebSocketServer) Stop(ctx context.Context) error {
-	return s.conn.Close()
+	log.Info("[WEBSOCKET] server stopping")
+	s.stopping.Store(true)
+	s.subMu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.subMu.Unlock()
+	// hijacked connections are not tracked by Shutdown, they're closed below
+	err := s.server.Shutdown(ctx)
+	if s.lis != nil {
+		// opened by Endpoint but never served, Shutdown only closes the served listeners
+		_ = s.lis.Close()
+	}
+	if err != nil {
+		return err
+	}
+	for _, sess := range s.hub.all() {
+		sess.closeWith(websocket.CloseGoingAway, nil)
+	}
+	done := make(chan struct{})
+	go func() {
+		s.sessions.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-// Start 启动 WebSocket 服务器
+// Start 订阅 Backplane 并启动 WebSocket 服务器，直到 Stop 被调用
 func (s *WebSocketServer) Start(ctx context.Context) error {
-	http.HandleFunc(s.url, s.wsHandler)
-	return http.ListenAndServe(s.addr, nil)
+	if err := s.Subscribe(ctx); err != nil {
+		return err
+	}
+	if err := s.listenAndEndpoint(); err != nil {
+		return err
+	}
+	s.server.BaseContext = func(net.Listener) context.Context {
+		return ctx
+	}
+	log.Infof("[WEBSOCKET] server listening on: %s", s.lis.Addr().String())
+
+	var err error
+	if s.tlsConf != nil || s.certFile != "" {
+		err = s.server.ServeTLS(s.lis, s.certFile, s.keyFile)
+	} else {
+		err = s.server.Serve(s.lis)
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
-// Endpoint 返回 WebSocket 服务器的地址
+// Endpoint 返回 WebSocket 服务器的地址，如 ws://192.168.1.10:8000/ws，启用 TLS 时为 wss。
+// 服务器会先开始监听，以获取 :0 的实际端口
 func (s *WebSocketServer) Endpoint() (*url.URL, error) {
-	prefix := "websocket://"
-	addr := prefix + s.addr
-	endpoint, err := url.Parse(addr)
-	return endpoint, err
+	if err := s.listenAndEndpoint(); err != nil {
+		return nil, err
+	}
+	return s.endpoint, nil
 }
 
-// WsHandler 处理 WebSocket 连接
+func (s *WebSocketServer) listenAndEndpoint() error {
+	if s.lis == nil {
+		lis, err := net.Listen(s.network, s.addr)
+		if err != nil {
+			return err
+		}
+		s.lis = lis
+	}
+	if s.endpoint == nil {
+		addr, err := host.Extract(s.addr, s.lis)
+		if err != nil {
+			return err
+		}
+		scheme := "ws"
+		if s.tlsConf != nil || s.certFile != "" {
+			scheme = "wss"
+		}
+		s.endpoint = &url.URL{Scheme: scheme, Host: addr, Path: s.url}
+	}
+	return nil
+}
+
+// Send 向指定连接发送消息
+func (s *WebSocketServer) Send(id string, msg []byte) error {
+	sess, ok := s.hub.get(id)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	return sess.Send(msg)
+}
+
+// Broadcast 向所有连接发送消息，设置了 Backplane 时同时发送给其它节点的连接
+func (s *WebSocketServer) Broadcast(msg []byte) {
+	s.send(s.hub.all(), msg)
+	s.publish(true, "", msg)
+}
+
+// BroadcastRoom 向房间内的所有连接发送消息，设置了 Backplane 时同时发送给其它节点的房间
+func (s *WebSocketServer) BroadcastRoom(room string, msg []byte) {
+	s.send(s.hub.room(room), msg)
+	s.publish(false, room, msg)
+}
+
+func (s *WebSocketServer) send(sessions []*Session, msg []byte) {
+	for _, sess := range sessions {
+		_ = sess.Send(msg)
+	}
+}
+
+// Join 将连接加入房间
+func (s *WebSocketServer) Join(id, room string) error {
+	sess, ok := s.hub.get(id)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	sess.Join(room)
+	return nil
+}
+
+// Leave 将连接移出房间
+func (s *WebSocketServer) Leave(id, room string) error {
+	sess, ok := s.hub.get(id)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	sess.Leave(room)
+	return nil
+}
+
+// Session 返回指定的连接
+func (s *WebSocketServer) Session(id string) (*Session, bool) {
+	return s.hub.get(id)
+}
+
+// Count 返回当前的连接数
+func (s *WebSocketServer) Count() int {
+	return s.hub.count()
+}
+
+// wsHandler 处理 WebSocket 连接
 func (s *WebSocketServer) wsHandler(res http.ResponseWriter, req *http.Request) {
+	if s.stopping.Load() {
+		http.Error(res, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	var auth any
+	if s.auth != nil {
+		var err error
+		if auth, err = s.auth(req, s.token(req)); err != nil {
+			http.Error(res, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
 	// 连接 WebSocket
 	conn, err := s.upgrader.Upgrade(res, req, nil)
 	if err != nil {
 		return
 	}
-	s.conn = conn
+	sess := newSession(uuid.NewString(), conn, req, s)
+	sess.auth = auth
+	s.sessions.Add(1)
+	s.hub.register(sess)
+	if s.stopping.Load() {
+		// Stop began after the check above, it may have missed the session
+		sess.closeWith(websocket.CloseGoingAway, nil)
+	}
+	if s.onConnect != nil {
+		s.onConnect(sess)
+	}
+	sess.serve()
+}
+
+// token returns the bearer token of the Authorization header, or the token query parameter.
+func (s *WebSocketServer) token(req *http.Request) string {
+	if scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return token
+	}
+	return req.URL.Query().Get(s.tokenQuery)
 }
@@ -0,0 +1,322 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+func dial(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func read(t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(msg)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebSocketServer_Hub(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		ids    []string
+		closed = make(chan string, 3)
+	)
+	s := NewWebSocketServer(
+		WithOnConnect(func(sess *Session) {
+			mu.Lock()
+			ids = append(ids, sess.ID())
+			mu.Unlock()
+		}),
+		WithOnMessage(func(sess *Session, msg []byte) {
+			if room, ok := strings.CutPrefix(string(msg), "join:"); ok {
+				sess.Join(room)
+				_ = sess.Send([]byte("joined " + room))
+				return
+			}
+			_ = sess.Send(append([]byte("echo "), msg...))
+		}),
+		WithOnClose(func(sess *Session, err error) {
+			closed <- sess.ID()
+		}),
+	)
+	srv := httptest.NewServer(http.HandlerFunc(s.wsHandler))
+	defer srv.Close()
+
+	c1, c2 := dial(t, srv), dial(t, srv)
+	defer c2.Close()
+	waitFor(t, func() bool { return s.Count() == 2 })
+
+	if err := c1.WriteMessage(websocket.TextMessage, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if msg := read(t, c1); msg != "echo hello" {
+		t.Errorf("unexpected echo %q", msg)
+	}
+
+	_ = c2.WriteMessage(websocket.TextMessage, []byte("join:news"))
+	if msg := read(t, c2); msg != "joined news" {
+		t.Errorf("unexpected reply %q", msg)
+	}
+	s.BroadcastRoom("news", []byte("room"))
+	s.Broadcast([]byte("all"))
+	if msg := read(t, c1); msg != "all" {
+		t.Errorf("expect c1 not in the room, got %q", msg)
+	}
+	if msg1, msg2 := read(t, c2), read(t, c2); msg1 != "room" || msg2 != "all" {
+		t.Errorf("unexpected messages of c2 %q %q", msg1, msg2)
+	}
+
+	mu.Lock()
+	first := ids[0]
+	mu.Unlock()
+	if err := s.Send(first, []byte("direct")); err != nil {
+		t.Fatal(err)
+	}
+	if msg := read(t, c1); msg != "direct" {
+		t.Errorf("unexpected direct message %q", msg)
+	}
+	if err := s.Send("missing", nil); err != ErrSessionNotFound {
+		t.Errorf("expect ErrSessionNotFound, got %v", err)
+	}
+
+	_ = c1.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	if id := <-closed; id != first {
+		t.Errorf("expect %s closed, got %s", first, id)
+	}
+	waitFor(t, func() bool { return s.Count() == 1 })
+	if err := s.Send(first, nil); err != ErrSessionNotFound {
+		t.Errorf("expect the closed session removed, got %v", err)
+	}
+	if sessions := s.hub.room("news"); len(sessions) != 1 {
+		t.Errorf("expect c2 in the room, got %d sessions", len(sessions))
+	}
+}
+
+func TestWebSocketServer_Keepalive(t *testing.T) {
+	s := NewWebSocketServer(WithPongWait(100 * time.Millisecond))
+	srv := httptest.NewServer(http.HandlerFunc(s.wsHandler))
+	defer srv.Close()
+
+	// the client reading answers the pings, and keeps the session alive
+	alive := dial(t, srv)
+	defer alive.Close()
+	go func() {
+		for {
+			if _, _, err := alive.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	// the client not reading never answers the pings
+	dial(t, srv)
+	waitFor(t, func() bool { return s.Count() == 2 })
+
+	time.Sleep(300 * time.Millisecond)
+	if n := s.Count(); n != 1 {
+		t.Errorf("expect the dead session closed, got %d sessions", n)
+	}
+}
+
+func TestWebSocketServer_Stop(t *testing.T) {
+	if err := NewWebSocketServer().Stop(context.Background()); err != nil {
+		t.Errorf("expect nil without sessions, got %v", err)
+	}
+
+	s := NewWebSocketServer()
+	srv := httptest.NewServer(http.HandlerFunc(s.wsHandler))
+	defer srv.Close()
+	conn := dial(t, srv)
+	defer conn.Close()
+	waitFor(t, func() bool { return s.Count() == 1 })
+	_ = s.Send(s.hub.all()[0].ID(), []byte("last"))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Stop(context.Background())
+	}()
+	if msg := read(t, conn); msg != "last" {
+		t.Errorf("expect the queued message sent before closing, got %q", msg)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("expect going away, got %v", err)
+	}
+	if err := <-done; err != nil || s.Count() != 0 {
+		t.Errorf("unexpected stop %v, %d sessions", err, s.Count())
+	}
+	if _, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil); err == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expect new connections rejected, got %v", err)
+	}
+}
+
+func TestWebSocketServer_Auth(t *testing.T) {
+	s := NewWebSocketServer(
+		WithAuth(func(r *http.Request, token string) (any, error) {
+			if token != "secret" {
+				return nil, errors.New("invalid token")
+			}
+			return "user-1", nil
+		}),
+		WithOnMessage(func(sess *Session, msg []byte) {
+			_ = sess.Send([]byte(sess.Auth().(string)))
+		}),
+	)
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	u := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	if _, resp, err := websocket.DefaultDialer.Dial(u, nil); err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expect 401 without token, got %v", err)
+	}
+	header := http.Header{"Authorization": {"Bearer secret"}}
+	for _, c := range []struct {
+		url    string
+		header http.Header
+	}{{u + "?token=secret", nil}, {u, header}} {
+		conn, _, err := websocket.DefaultDialer.Dial(c.url, c.header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("who"))
+		if msg := read(t, conn); msg != "user-1" {
+			t.Errorf("unexpected auth %q", msg)
+		}
+		_ = conn.Close()
+	}
+}
+
+func TestWebSocketServer_Origin(t *testing.T) {
+	tests := []struct {
+		name    string
+		allow   []string
+		origin  string
+		allowed bool
+	}{
+		{"same origin by default", nil, "", true},
+		{"cross origin by default", nil, "https://evil.com", false},
+		{"allowed", []string{"https://*.example.com"}, "https://app.example.com", true},
+		{"not allowed", []string{"https://*.example.com"}, "https://evil.com", false},
+		{"no origin", []string{"https://app.example.com"}, "-", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(NewWebSocketServer(WithAllowOrigins(tt.allow...)).Handler())
+			defer srv.Close()
+			header := http.Header{}
+			switch tt.origin {
+			case "":
+				header.Set("Origin", srv.URL)
+			case "-":
+			default:
+				header.Set("Origin", tt.origin)
+			}
+			conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), header)
+			if err == nil {
+				_ = conn.Close()
+			}
+			if (err == nil) != tt.allowed {
+				t.Errorf("expect allowed %v, got %v", tt.allowed, err)
+			}
+		})
+	}
+}
+
+func TestWebSocketServer_MountGin(t *testing.T) {
+	s := NewWebSocketServer(WithOnMessage(func(sess *Session, msg []byte) {
+		_ = sess.Send(msg)
+	}))
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/ws", gin.WrapH(s.Handler()))
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+	if msg := read(t, conn); msg != "ping" {
+		t.Errorf("unexpected echo %q", msg)
+	}
+}
+
+func TestWebSocketServer_StartStop(t *testing.T) {
+	s := NewWebSocketServer(WithAddr("127.0.0.1:0"), WithURL("/ws"))
+	endpoint, err := s.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endpoint.Scheme != "ws" || endpoint.Path != "/ws" || strings.HasSuffix(endpoint.Host, ":0") {
+		t.Errorf("unexpected endpoint %s", endpoint)
+	}
+	started := make(chan error, 1)
+	go func() {
+		started <- s.Start(context.Background())
+	}()
+
+	var conn *websocket.Conn
+	waitFor(t, func() bool {
+		conn, _, err = websocket.DefaultDialer.Dial(endpoint.String(), nil)
+		return err == nil
+	})
+	defer conn.Close()
+	waitFor(t, func() bool { return s.Count() == 1 })
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-started; err != nil {
+		t.Errorf("expect nil after stop, got %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("expect going away, got %v", err)
+	}
+}
+
+func TestWebSocketServer_StopWithoutStart(t *testing.T) {
+	s := NewWebSocketServer(WithAddr("127.0.0.1:0"))
+	endpoint, err := s.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if conn, err := net.Dial("tcp", endpoint.Host); err == nil {
+		_ = conn.Close()
+		t.Error("expect the pre-opened listener closed")
+	}
+}
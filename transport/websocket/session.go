@@ -0,0 +1,187 @@
+package websocket
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	// ErrSessionClosed 连接已关闭
+	ErrSessionClosed = errors.New("websocket: session closed")
+	// ErrSessionNotFound 连接不存在
+	ErrSessionNotFound = errors.New("websocket: session not found")
+	// ErrSendBufferFull 发送缓冲区已满，客户端接收过慢，连接会被关闭
+	ErrSendBufferFull = errors.New("websocket: send buffer full")
+)
+
+// Session 是一个 WebSocket 连接，发送的消息由单独的写协程写入
+type Session struct {
+	id      string
+	conn    *websocket.Conn
+	request *http.Request
+	server  *WebSocketServer
+	auth    any
+
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	closeCode int
+	closeErr  error
+	wg        sync.WaitGroup
+}
+
+func newSession(id string, conn *websocket.Conn, req *http.Request, s *WebSocketServer) *Session {
+	return &Session{
+		id:        id,
+		conn:      conn,
+		request:   req,
+		server:    s,
+		send:      make(chan []byte, s.sendBuffer),
+		done:      make(chan struct{}),
+		closeCode: websocket.CloseNormalClosure,
+	}
+}
+
+// ID 返回连接 ID
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Request 返回建立连接的 HTTP 请求
+func (s *Session) Request() *http.Request {
+	return s.request
+}
+
+// Auth 返回 AuthFunc 认证后返回的信息，未设置 AuthFunc 时为 nil
+func (s *Session) Auth() any {
+	return s.auth
+}
+
+// Send 发送消息，缓冲区已满时关闭连接并返回 ErrSendBufferFull
+func (s *Session) Send(msg []byte) error {
+	select {
+	case <-s.done:
+		return ErrSessionClosed
+	default:
+	}
+	select {
+	case s.send <- msg:
+		return nil
+	case <-s.done:
+		return ErrSessionClosed
+	default:
+		s.closeWith(websocket.ClosePolicyViolation, ErrSendBufferFull)
+		return ErrSendBufferFull
+	}
+}
+
+// Join 加入房间
+func (s *Session) Join(room string) {
+	s.server.hub.join(s, room)
+}
+
+// Leave 离开房间
+func (s *Session) Leave(room string) {
+	s.server.hub.leave(s, room)
+}
+
+// Close 发送完缓冲区中的消息后关闭连接
+func (s *Session) Close() error {
+	s.closeWith(websocket.CloseNormalClosure, nil)
+	return nil
+}
+
+// closeWith closes the session once, the close code is sent to the client and err is passed to OnClose.
+func (s *Session) closeWith(code int, err error) {
+	s.closeOnce.Do(func() {
+		s.closeCode, s.closeErr = code, err
+		close(s.done)
+	})
+}
+
+// serve runs the pumps, and calls OnClose after both exit.
+func (s *Session) serve() {
+	s.wg.Add(2)
+	go s.writePump()
+	go s.readPump()
+	go func() {
+		s.wg.Wait()
+		s.server.hub.unregister(s)
+		if s.server.onClose != nil {
+			s.server.onClose(s, s.closeErr)
+		}
+		s.server.sessions.Done()
+	}()
+}
+
+func (s *Session) readPump() {
+	defer s.wg.Done()
+	s.conn.SetReadLimit(s.server.maxMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(s.server.pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(s.server.pongWait))
+	})
+	for {
+		_, msg, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
+				err = nil
+			}
+			select {
+			case <-s.done:
+				// closed by the server, keep its error
+			default:
+				s.closeWith(websocket.CloseNormalClosure, err)
+			}
+			return
+		}
+		if s.server.onMessage != nil {
+			s.server.onMessage(s, msg)
+		}
+	}
+}
+
+func (s *Session) writePump() {
+	ticker := time.NewTicker(s.server.pingPeriod)
+	defer func() {
+		ticker.Stop()
+		_ = s.conn.Close()
+		s.wg.Done()
+	}()
+	for {
+		select {
+		case msg := <-s.send:
+			if err := s.write(s.server.messageType, msg); err != nil {
+				s.closeWith(websocket.CloseAbnormalClosure, err)
+				return
+			}
+		case <-ticker.C:
+			if err := s.write(websocket.PingMessage, nil); err != nil {
+				s.closeWith(websocket.CloseAbnormalClosure, err)
+				return
+			}
+		case <-s.done:
+			// drain the queued messages before the close frame
+			for {
+				select {
+				case msg := <-s.send:
+					if err := s.write(s.server.messageType, msg); err != nil {
+						return
+					}
+				default:
+					_ = s.write(websocket.CloseMessage, websocket.FormatCloseMessage(s.closeCode, ""))
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *Session) write(messageType int, data []byte) error {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(s.server.writeWait))
+	return s.conn.WriteMessage(messageType, data)
+}
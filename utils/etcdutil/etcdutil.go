@@ -2,32 +2,58 @@ package etcdutil
 
 import (
 	"context"
-	"fmt"
+
+	klog "github.com/go-kratos/kratos/v2/log"
+	"github.com/jiushengTech/common/log"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
+// Option 是 etcdutil 的选项类型
+type Option func(*Etcd)
+
+// WithLogger 设置日志记录器，默认使用 log.GetLogger()
+func WithLogger(l log.Logger) Option {
+	return func(e *Etcd) {
+		e.log = log.NewHelper(l)
+	}
+}
+
 type Etcd struct {
 	Client  *clientv3.Client
 	Kv      clientv3.KV
 	Lease   clientv3.Lease
 	Watcher clientv3.Watcher
+
+	log *klog.Helper
 }
 
 var ETCD Etcd
 
-func NewEtcdUtil(e Etcd) {
+// logger 返回日志记录器，直接赋值 ETCD 而未设置时使用 log.GetLogger()
+func (e *Etcd) logger() *klog.Helper {
+	if e.log == nil {
+		return log.NewHelper(log.GetLogger())
+	}
+	return e.log
+}
+
+func NewEtcdUtil(e Etcd, opts ...Option) {
+	e.log = log.NewHelper(log.GetLogger())
+	for _, o := range opts {
+		o(&e)
+	}
 	ETCD = e
 }
 
 func GetKeyValue(key string) error {
 	getResp, err := ETCD.Kv.Get(context.TODO(), key, clientv3.WithPrefix())
 	if err != nil {
-		fmt.Println(err)
+		ETCD.logger().Error(err)
 	}
 
 	// 遍历所有任务, 进行反序列化
 	for _, kvPair := range getResp.Kvs {
-		fmt.Println(kvPair)
+		ETCD.logger().Debug(kvPair)
 	}
 	return err
 }
@@ -36,11 +62,11 @@ func PutKeyValue(key, value string) error {
 	// 保存到etcd
 	putResp, err := ETCD.Kv.Put(context.TODO(), key, value, clientv3.WithPrevKV())
 	if err != nil {
-		fmt.Println(err)
+		ETCD.logger().Error(err)
 	}
 	// 如果是更新, 那么返回旧值
 	if putResp.PrevKv != nil {
-		fmt.Println(putResp.PrevKv.Value)
+		ETCD.logger().Debugf("prev value: %s", putResp.PrevKv.Value)
 	}
 	return err
 }
@@ -49,11 +75,11 @@ func DeleteKey(key string) error {
 	// 从etcd中删除它
 	delResp, err := ETCD.Kv.Delete(context.TODO(), key, clientv3.WithPrevKV())
 	if err != nil {
-		fmt.Println(err)
+		ETCD.logger().Error(err)
 	}
 	// 返回被删除的值
 	if len(delResp.PrevKvs) != 0 {
-		fmt.Println(delResp.PrevKvs[0].Value)
+		ETCD.logger().Debugf("deleted value: %s", delResp.PrevKvs[0].Value)
 	}
 	return err
 }
@@ -77,7 +103,7 @@ func PutKeyWithLease(key string, timeout int64) (err error) {
 func GetValueByKey(key string) (string, error) {
 	getResp, err := ETCD.Kv.Get(context.TODO(), key)
 	if err != nil {
-		fmt.Println(err)
+		ETCD.logger().Error(err)
 		return "", err
 	}
 
@@ -7,8 +7,33 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	klog "github.com/go-kratos/kratos/v2/log"
+	"github.com/jiushengTech/common/log"
 )
 
+// Option 是 CleanUp 和下载函数的选项类型
+type Option func(*options)
+
+type options struct {
+	logger log.Logger
+}
+
+// WithLogger 设置日志记录器，默认使用 log.GetLogger()
+func WithLogger(l log.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+func newLogger(opts []Option) *klog.Helper {
+	o := options{logger: log.GetLogger()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return log.NewHelper(o.logger)
+}
+
 // FormatFileSize 格式化文件大小，使用二进制单位
 func FormatFileSize(fileSize int64) string {
 	const (
@@ -37,7 +62,8 @@ func FormatFileSize(fileSize int64) string {
 }
 
 // CleanUp 删除指定路径的文件或目录，并根据 deleteDirectoryContents 参数删除该文件所在目录下的所有文件和文件夹
-func CleanUp(path string, deleteDirectoryContents bool) error {
+func CleanUp(path string, deleteDirectoryContents bool, opts ...Option) error {
+	logger := newLogger(opts)
 
 	// 检查路径是否存在
 	info, err := os.Stat(path)
@@ -53,21 +79,21 @@ func CleanUp(path string, deleteDirectoryContents bool) error {
 		if err := os.Remove(path); err != nil {
 			return fmt.Errorf("error removing file: %v", err)
 		}
-		fmt.Printf("File %s removed successfully\n", path)
+		logger.Infof("File %s removed successfully", path)
 
 		// 如果需要删除文件所在目录下的所有文件和文件夹
 		if deleteDirectoryContents {
 			dir := filepath.Dir(path)
-			if err := removeDirectoryContents(dir); err != nil {
+			if err := removeDirectoryContents(logger, dir); err != nil {
 				return fmt.Errorf("error deleting directory contents: %v", err)
 			}
-			fmt.Printf("All files and subdirectories in directory %s have been removed.\n", dir)
+			logger.Infof("All files and subdirectories in directory %s have been removed.", dir)
 		}
 		return nil
 	}
 
 	// 如果是目录，删除目录下所有内容
-	if err := removeDirectoryContents(path); err != nil {
+	if err := removeDirectoryContents(logger, path); err != nil {
 		return fmt.Errorf("error deleting directory contents: %v", err)
 	}
 
@@ -75,13 +101,13 @@ func CleanUp(path string, deleteDirectoryContents bool) error {
 	if err := os.Remove(path); err != nil {
 		return fmt.Errorf("error removing directory: %v", err)
 	}
-	fmt.Printf("Directory %s removed successfully\n", path)
+	logger.Infof("Directory %s removed successfully", path)
 
 	return nil
 }
 
 // removeDirectoryContents 删除目录下的所有文件和子目录，但保留目录本身
-func removeDirectoryContents(dir string) error {
+func removeDirectoryContents(logger *klog.Helper, dir string) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return fmt.Errorf("error reading directory %s: %v", dir, err)
@@ -93,9 +119,9 @@ func removeDirectoryContents(dir string) error {
 			return fmt.Errorf("error removing %s: %v", path, err)
 		}
 		if entry.IsDir() {
-			fmt.Printf("Directory %s removed successfully\n", path)
+			logger.Infof("Directory %s removed successfully", path)
 		} else {
-			fmt.Printf("File %s removed successfully\n", path)
+			logger.Infof("File %s removed successfully", path)
 		}
 	}
 
@@ -103,7 +129,7 @@ func removeDirectoryContents(dir string) error {
 }
 
 // DownloadFile 根据给定的 URL 下载文件并保存到本地，返回本地文件路径
-func DownloadFile(url, downloadDir string) (string, error) {
+func DownloadFile(url, downloadDir string, opts ...Option) (string, error) {
 	// 解析文件名
 	fileName := filepath.Base(url)
 	if fileName == "" || fileName == "." || fileName == "/" {
@@ -124,7 +150,7 @@ func DownloadFile(url, downloadDir string) (string, error) {
 	filePath := filepath.Join(downloadDir, fileName)
 
 	// 下载文件内容
-	data, err := DownloadFileToBytes(url)
+	data, err := DownloadFileToBytes(url, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -134,12 +160,12 @@ func DownloadFile(url, downloadDir string) (string, error) {
 		return "", fmt.Errorf("error writing file %s: %v", filePath, err)
 	}
 
-	fmt.Printf("File downloaded successfully: %s\n", filePath)
+	newLogger(opts).Infof("File downloaded successfully: %s", filePath)
 	return filePath, nil
 }
 
 // DownloadFileToBytes 从指定URL下载文件，返回二进制数据
-func DownloadFileToBytes(url string) ([]byte, error) {
+func DownloadFileToBytes(url string, opts ...Option) ([]byte, error) {
 	// 创建HTTP客户端，设置超时
 	client := &http.Client{
 		Timeout: 30 * time.Second,
@@ -172,12 +198,12 @@ func DownloadFileToBytes(url string) ([]byte, error) {
 		return nil, fmt.Errorf("error reading response body: %v", err)
 	}
 
-	fmt.Printf("File downloaded to memory: %d bytes from %s\n", len(data), url)
+	newLogger(opts).Infof("File downloaded to memory: %d bytes from %s", len(data), url)
 	return data, nil
 }
 
 // DownloadFileWithProgressToBytes 从指定URL下载文件，返回二进制数据，支持进度回调
-func DownloadFileWithProgressToBytes(url string, progressCallback func(downloaded, total int64)) ([]byte, error) {
+func DownloadFileWithProgressToBytes(url string, progressCallback func(downloaded, total int64), opts ...Option) ([]byte, error) {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
 	}
@@ -217,7 +243,7 @@ func DownloadFileWithProgressToBytes(url string, progressCallback func(downloade
 		return nil, fmt.Errorf("error reading response body: %v", err)
 	}
 
-	fmt.Printf("File downloaded to memory: %d bytes from %s\n", len(data), url)
+	newLogger(opts).Infof("File downloaded to memory: %d bytes from %s", len(data), url)
 	return data, nil
 }
 
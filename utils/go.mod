@@ -4,9 +4,8 @@ go 1.24.2
 
 require (
 	github.com/fogleman/gg v1.3.0
+	github.com/go-kratos/kratos/v2 v2.8.4
 	github.com/jiushengTech/common v0.0.0-20250613100701-6d1864d2f9d0
-	github.com/sony/sonyflake v1.2.1
-	github.com/sony/sonyflake/v2 v2.2.0
 	github.com/tealeg/xlsx v1.0.5
 	go.etcd.io/etcd/client/v3 v3.6.1
 	golang.org/x/image v0.28.0
@@ -33,7 +32,6 @@ require (
 	google.golang.org/genproto/googleapis/api v0.0.0-20250603155806-513f23925822 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250603155806-513f23925822 // indirect
 	google.golang.org/grpc v1.73.0 // indirect
-	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
 )
 
 replace github.com/jiushengTech/common => ../
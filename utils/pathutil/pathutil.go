@@ -7,6 +7,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/jiushengTech/common/internal/datedir"
 )
 
 // GetCurrentPath 返回调用者所在文件的目录路径
@@ -36,11 +38,7 @@ func GetProjectRoot() string {
 
 // GetCurrentDateOnlyAsDir 在指定路径下拼接当前日期目录 (YYYY-MM-DD)
 func GetCurrentDateOnlyAsDir(basePath string) string {
-	format := time.Now().Format(time.DateOnly)
-	if basePath == "" {
-		return format
-	}
-	return filepath.Join(basePath, format)
+	return datedir.Join(basePath)
 }
 
 // GetCurrentDateOnlyAsDirSlash 在输入目录后拼接日期目录（格式：YYYY-MM-DD）